@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"holdem/handevaluator"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	out := flag.String("o", "HandRanks.dat", "where to write the generated table")
+	header := flag.Bool("header", false, "precede the table with a versioned header holding its size and checksum")
+	compare := flag.String("compare", "", "an existing table to compare the generated one against instead of writing it")
+	flag.Parse()
+
+	t := time.Now()
+	ranks := handevaluator.Generate()
+	fmt.Printf("generated %d entries in %s\n", len(ranks), time.Since(t))
+
+	if *compare != "" {
+		if err := compareWith(*compare, ranks); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(*compare + " is identical to the generated table")
+		return
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	write := handevaluator.WriteTable
+	if *header {
+		write = handevaluator.WriteVersionedTable
+	}
+
+	if err := write(file, ranks); err != nil {
+		file.Close()
+		log.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("wrote " + *out)
+}
+
+func compareWith(path string, ranks []uint32) error {
+
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if len(buffer) != 4*len(ranks) {
+		return fmt.Errorf("%s has %d entries, generated table has %d", path, len(buffer)/4, len(ranks))
+	}
+
+	mismatches := 0
+	for i, r := range ranks {
+		if loaded := binary.LittleEndian.Uint32(buffer[4*i:]); loaded != r {
+			if mismatches < 10 {
+				fmt.Printf("entry %d: %s has %d, generated %d\n", i, path, loaded, r)
+			}
+			mismatches++
+		}
+	}
+
+	if mismatches > 0 {
+		return fmt.Errorf("%d entries differ", mismatches)
+	}
+	return nil
+}
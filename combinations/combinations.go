@@ -3,15 +3,17 @@ package combinations
 import (
 	"fmt"
 	"holdem/list"
+	"sync"
 	"time"
 )
 
 type Combinations struct {
 	store map[uint16][][]uint8
+	mutex *sync.RWMutex
 }
 
 func New() Combinations {
-	h := Combinations{}
+	h := Combinations{mutex: &sync.RWMutex{}}
 	h.intialize()
 	return h
 }
@@ -38,15 +40,19 @@ func (c *Combinations) intialize() {
 
 	c.store = map[uint16][][]uint8{}
 	for _, e := range villainCombinations {
+		c.store[key(e[0], e[1])] = timedGenerate(e[0], e[1])
+	}
+}
 
-		t := time.Now()
-		key, combos := generate(e[0], e[1])
-		fmt.Printf("%v t:%f l:%d\n", e, time.Since(t).Minutes(), len(combos))
-		if len(combos) > 1<<31-1 {
-			panic("can't use Rand.Int31 for sampling")
-		}
-		c.store[key] = combos
+func timedGenerate(n uint8, r uint8) [][]uint8 {
+
+	t := time.Now()
+	_, combos := generate(n, r)
+	fmt.Printf("%v t:%f l:%d\n", []uint8{n, r}, time.Since(t).Minutes(), len(combos))
+	if len(combos) > 1<<31-1 {
+		panic("can't use Rand.Int31 for sampling")
 	}
+	return combos
 }
 
 func generate(n uint8, r uint8) (uint16, [][]uint8) {
@@ -105,17 +111,47 @@ func key(n uint8, r uint8) uint16 {
 	return uint16(r) | uint16(n)<<8
 }
 
+// maxGenerated limits the combinations generated on demand, 52 c 5 is the
+// largest any game needs.
+const maxGenerated = 2598960
+
+// Get returns every way of choosing r of n indexes, generating and keeping
+// the ones that weren't prepared up front.
 func (c *Combinations) Get(n uint8, r uint8) ([][]uint8, error) {
 
+	c.mutex.RLock()
 	res, ok := c.store[key(n, r)]
+	c.mutex.RUnlock()
+
+	if ok {
+		return res, nil
+	}
 
-	if !ok {
+	if r > n || count(n, r) > maxGenerated {
 		return nil, fmt.Errorf("unable to compute %d c %d", n, r)
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if res, ok := c.store[key(n, r)]; ok {
+		return res, nil
+	}
+
+	res = timedGenerate(n, r)
+	c.store[key(n, r)] = res
 	return res, nil
 }
 
+// count returns n c r.
+func count(n uint8, r uint8) int {
+	result := 1
+	for i := 0; i < int(r); i++ {
+		result = result * (int(n) - i) / (i + 1)
+	}
+	return result
+}
+
 // func (c *Combinations) GetAllPossiblePairs(available []int) ([][]int, map[int]map[int]int, error) {
 // 	combos, err := c.Get(len(available), 2)
 
@@ -0,0 +1,166 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Rank uint8
+
+const (
+	Two Rank = iota
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+	Ace
+)
+
+type Suit uint8
+
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+const rankLetters = "23456789tjqka"
+const suitLetters = "cdhs"
+
+// Card is a playing card numbered the way the hand evaluators number cards:
+// 1 is the 2c, 2 the 2d and so on to 52, the as. 0 is no card.
+type Card uint8
+
+func NewCard(rank Rank, suit Suit) Card {
+	return Card(uint8(rank)<<2 | uint8(suit) + 1)
+}
+
+// CardFromNumber converts the evaluators' encoding into a card.
+func CardFromNumber(number uint8) (Card, error) {
+	if number < 1 || number > 52 {
+		return 0, fmt.Errorf("%d is not a valid card", number)
+	}
+	return Card(number), nil
+}
+
+// ParseCard reads a single card in any notation ParseHand accepts.
+func ParseCard(s string) (Card, error) {
+
+	cards, err := ParseHand(s)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(cards) != 1 {
+		return 0, fmt.Errorf("%s is not a single card", s)
+	}
+
+	return cards[0], nil
+}
+
+// ParseCards reads the cards of every string, each holding one or more
+// cards, for example the values of a repeated query parameter.
+func ParseCards(strs []string) ([]Card, error) {
+
+	cards := []Card{}
+
+	for _, s := range strs {
+		parsed, err := ParseHand(s)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, parsed...)
+	}
+
+	return cards, nil
+}
+
+func (c Card) Valid() bool {
+	return c >= 1 && c <= 52
+}
+
+func (c Card) Rank() Rank {
+	return Rank((c - 1) >> 2)
+}
+
+func (c Card) Suit() Suit {
+	return Suit((c - 1) & 3)
+}
+
+// Number is the card in the evaluators' encoding.
+func (c Card) Number() uint8 {
+	return uint8(c)
+}
+
+func (c Card) String() string {
+	if !c.Valid() {
+		return "invalid"
+	}
+	return c.Rank().String() + c.Suit().String()
+}
+
+func (c Card) MarshalJSON() ([]byte, error) {
+	if !c.Valid() {
+		return nil, fmt.Errorf("%d is not a valid card", uint8(c))
+	}
+	return json.Marshal(c.String())
+}
+
+func (c *Card) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseCard(s)
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+func (r Rank) String() string {
+	if r > Ace {
+		return "?"
+	}
+	return rankLetters[r : r+1]
+}
+
+func (s Suit) String() string {
+	if s > Spades {
+		return "?"
+	}
+	return suitLetters[s : s+1]
+}
+
+// Numbers converts cards into the evaluators' encoding.
+func Numbers(cards []Card) []uint8 {
+	numbers := make([]uint8, len(cards))
+	for i, c := range cards {
+		numbers[i] = c.Number()
+	}
+	return numbers
+}
+
+// FromNumbers converts cards in the evaluators' encoding into cards.
+func FromNumbers(numbers []uint8) ([]Card, error) {
+	cards := make([]Card, len(numbers))
+	for i, n := range numbers {
+		c, err := CardFromNumber(n)
+		if err != nil {
+			return nil, err
+		}
+		cards[i] = c
+	}
+	return cards, nil
+}
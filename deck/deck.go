@@ -1,168 +1,79 @@
 package deck
 
 import (
-	"errors"
 	"fmt"
-	"strings"
 )
 
-type stringNumberPair struct {
-	str    string
-	number uint8
-}
-
 type Deck struct {
-	stringNumberPair []stringNumberPair
-	stringToNumber   map[string]uint8
-	numberToString   map[uint8]string
+	cards []Card
 }
 
 func New() Deck {
-	deck := Deck{
-		stringToNumber: map[string]uint8{},
-		numberToString: map[uint8]string{},
-		stringNumberPair: []stringNumberPair{
-			{"2c", 1},
-			{"2d", 2},
-			{"2h", 3},
-			{"2s", 4},
-			{"3c", 5},
-			{"3d", 6},
-			{"3h", 7},
-			{"3s", 8},
-			{"4c", 9},
-			{"4d", 10},
-			{"4h", 11},
-			{"4s", 12},
-			{"5c", 13},
-			{"5d", 14},
-			{"5h", 15},
-			{"5s", 16},
-			{"6c", 17},
-			{"6d", 18},
-			{"6h", 19},
-			{"6s", 20},
-			{"7c", 21},
-			{"7d", 22},
-			{"7h", 23},
-			{"7s", 24},
-			{"8c", 25},
-			{"8d", 26},
-			{"8h", 27},
-			{"8s", 28},
-			{"9c", 29},
-			{"9d", 30},
-			{"9h", 31},
-			{"9s", 32},
-			{"tc", 33},
-			{"td", 34},
-			{"th", 35},
-			{"ts", 36},
-			{"jc", 37},
-			{"jd", 38},
-			{"jh", 39},
-			{"js", 40},
-			{"qc", 41},
-			{"qd", 42},
-			{"qh", 43},
-			{"qs", 44},
-			{"kc", 45},
-			{"kd", 46},
-			{"kh", 47},
-			{"ks", 48},
-			{"ac", 49},
-			{"ad", 50},
-			{"ah", 51},
-			{"as", 52},
-		},
-	}
-
-	for _, pair := range deck.stringNumberPair {
-		deck.numberToString[pair.number] = pair.str
-		deck.stringToNumber[pair.str] = pair.number
-	}
-
-	return deck
+	return newFromRank(Two)
 }
 
-func (d *Deck) SameSuit(c []uint8) bool {
-
-	lastSuit := ""
-
-	cards, err := d.CardNumbersToStrings(c)
-
-	if err != nil {
-		return false
-	}
+// NewShortDeck returns the 36 card deck of short deck poker, sixes through
+// aces. Cards keep the numbers they have in the full deck.
+func NewShortDeck() Deck {
+	return newFromRank(Six)
+}
 
-	for _, c := range cards {
+func newFromRank(lowest Rank) Deck {
 
-		current := c[1:]
+	deck := Deck{}
 
-		switch {
-		case lastSuit == current:
-		case lastSuit == "":
-			lastSuit = current
-		default:
-			return false
+	for r := lowest; r <= Ace; r++ {
+		for s := Clubs; s <= Spades; s++ {
+			deck.cards = append(deck.cards, NewCard(r, s))
 		}
 	}
 
-	return true
+	return deck
 }
 
-func (d *Deck) NumberToString(c uint8) string {
-
-	if s, ok := d.numberToString[c]; ok {
-		return s
-	}
+// Cards returns every card of the deck, lowest first.
+func (d *Deck) Cards() []Card {
+	return append([]Card(nil), d.cards...)
+}
 
-	return "invalid"
+func (d *Deck) Contains(c Card) bool {
+	return indexOf(d.cards, c) >= 0
 }
 
-func (d *Deck) CardNumbersToStrings(cards []uint8) ([]string, error) {
+// Parse reads cards such as "Ah" and checks that they belong to the deck.
+func (d *Deck) Parse(strs []string) ([]Card, error) {
 
-	mapped := make([]string, len(cards))
+	cards, err := ParseCards(strs)
 
-	for i, n := range cards {
+	if err != nil {
+		return nil, err
+	}
 
-		str, ok := d.numberToString[n]
-		if !ok {
-			return nil, fmt.Errorf("%d is not a valid card", n)
+	for _, c := range cards {
+		if !d.Contains(c) {
+			return nil, fmt.Errorf("%s is not in the deck", c)
 		}
-		mapped[i] = str
 	}
 
-	return mapped, nil
+	return cards, nil
 }
 
-func (d *Deck) CardStringsToNumbers(cards []string) ([]uint8, error) {
-
-	mapped := make([]uint8, len(cards))
+func (d *Deck) SameSuit(cards []Card) bool {
 
-	for i, s := range cards {
-
-		number, ok := d.stringToNumber[strings.ToLower(s)]
-		if !ok {
-			return nil, errors.New(s + " is not a valid card")
+	for _, c := range cards {
+		if c.Suit() != cards[0].Suit() {
+			return false
 		}
-		mapped[i] = number
 	}
 
-	return mapped, nil
+	return true
 }
 
 func (d *Deck) AllNumberValues() []uint8 {
 
-	if len(d.stringToNumber) != 52 {
+	if len(d.cards) == 0 {
 		panic("deck not complete")
 	}
 
-	result := make([]uint8, len(d.stringToNumber))
-
-	for i, v := range d.stringNumberPair {
-		result[i] = v.number
-	}
-
-	return result
+	return Numbers(d.cards)
 }
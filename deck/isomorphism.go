@@ -0,0 +1,116 @@
+package deck
+
+import (
+	"sort"
+	"strings"
+)
+
+// suitPermutations holds all 24 ways to relabel the four suits.
+var suitPermutations = buildSuitPermutations()
+
+func buildSuitPermutations() [][4]Suit {
+
+	permutations := [][4]Suit{}
+
+	var permute func(p [4]Suit, k int)
+	permute = func(p [4]Suit, k int) {
+		if k == len(p) {
+			permutations = append(permutations, p)
+			return
+		}
+		for i := k; i < len(p); i++ {
+			p[k], p[i] = p[i], p[k]
+			permute(p, k+1)
+			p[k], p[i] = p[i], p[k]
+		}
+	}
+	permute([4]Suit{Clubs, Diamonds, Hearts, Spades}, 0)
+
+	return permutations
+}
+
+// Canonical is the representative of a hand and board among all the hands
+// and boards that only differ by renaming suits, and which play the same.
+type Canonical struct {
+	Hero  []Card
+	Board []Card
+	// Variants is how many distinct hands and boards share this form.
+	Variants int
+}
+
+// Key identifies the canonical form, for example as a cache key.
+func (c Canonical) Key() string {
+
+	hero := make([]string, len(c.Hero))
+	for i, h := range c.Hero {
+		hero[i] = h.String()
+	}
+
+	board := make([]string, len(c.Board))
+	for i, b := range c.Board {
+		board[i] = b.String()
+	}
+
+	return strings.Join(hero, "") + "|" + strings.Join(board, "")
+}
+
+// boardStreets splits a board into the cards whose order doesn't matter: the
+// flop, the turn and the river.
+func boardStreets(board []Card) [][]Card {
+
+	if len(board) <= 3 {
+		return [][]Card{board}
+	}
+
+	streets := [][]Card{board[:3]}
+	for i := 3; i < len(board); i++ {
+		streets = append(streets, board[i:i+1])
+	}
+	return streets
+}
+
+// Canonicalize maps a hand and board to their canonical suit relabelling.
+// The hole cards and the flop are unordered, while the turn and river keep
+// their places, so the 1326 starting hands give 169 canonical forms and the
+// 22100 flops 1755.
+func Canonicalize(hero []Card, board []Card) Canonical {
+
+	groups := append([][]Card{hero}, boardStreets(board)...)
+
+	var best []Card
+	seen := map[string]struct{}{}
+
+	for _, p := range suitPermutations {
+
+		relabelled := []Card{}
+		for _, group := range groups {
+			g := make([]Card, len(group))
+			for i, c := range group {
+				g[i] = NewCard(c.Rank(), p[c.Suit()])
+			}
+			sort.Slice(g, func(i, j int) bool { return g[i] > g[j] })
+			relabelled = append(relabelled, g...)
+		}
+
+		seen[string(Numbers(relabelled))] = struct{}{}
+
+		if best == nil || lessCards(relabelled, best) {
+			best = relabelled
+		}
+	}
+
+	return Canonical{
+		Hero:     best[:len(hero)],
+		Board:    best[len(hero):],
+		Variants: len(seen),
+	}
+}
+
+func lessCards(a []Card, b []Card) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
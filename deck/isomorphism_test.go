@@ -0,0 +1,92 @@
+package deck
+
+import "testing"
+
+// countClasses canonicalizes every way of taking k cards of the deck as the
+// hero's hand or as the flop, and returns how many canonical forms there are
+// and how many variants they add up to.
+func countClasses(t *testing.T, k int, asHero bool) (int, int) {
+	t.Helper()
+
+	d := New()
+	cards := d.Cards()
+	classes := map[string]int{}
+	total := 0
+
+	var choose func(from int, chosen []Card)
+	choose = func(from int, chosen []Card) {
+		if len(chosen) == k {
+			total++
+			var c Canonical
+			if asHero {
+				c = Canonicalize(chosen, nil)
+			} else {
+				c = Canonicalize(nil, chosen)
+			}
+			if variants, ok := classes[c.Key()]; ok && variants != c.Variants {
+				t.Fatalf("%s has %d and %d variants", c.Key(), variants, c.Variants)
+			}
+			classes[c.Key()] = c.Variants
+			return
+		}
+		for i := from; i < len(cards); i++ {
+			choose(i+1, append(chosen, cards[i]))
+		}
+	}
+	choose(0, []Card{})
+
+	variants := 0
+	for _, v := range classes {
+		variants += v
+	}
+	if variants != total {
+		t.Errorf("%d canonical forms add up to %d variants, expected %d", len(classes), variants, total)
+	}
+
+	return len(classes), total
+}
+
+func TestCanonicalStartingHands(t *testing.T) {
+	if classes, total := countClasses(t, 2, true); classes != 169 || total != 1326 {
+		t.Errorf("%d starting hands give %d canonical forms, expected 1326 and 169", total, classes)
+	}
+}
+
+func TestCanonicalFlops(t *testing.T) {
+	if classes, total := countClasses(t, 3, false); classes != 1755 || total != 22100 {
+		t.Errorf("%d flops give %d canonical forms, expected 22100 and 1755", total, classes)
+	}
+}
+
+func TestCanonicalizeKeepsTurnAndRiver(t *testing.T) {
+
+	hero, err := ParseHand("AhKh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	turnFirst, err := ParseHand("Qh7c2d5s9s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	riverFirst, err := ParseHand("Qh7c2d9s5s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameSuits, err := ParseHand("Qs7d2c5h9h")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := Canonicalize(hero, turnFirst)
+	if Canonicalize(hero, riverFirst).Key() == c.Key() {
+		t.Errorf("the turn and river swapped give the same form %s", c.Key())
+	}
+
+	heroSameSuits, err := ParseHand("AsKs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other := Canonicalize(heroSameSuits, sameSuits); other.Key() != c.Key() {
+		t.Errorf("renaming suits gives %s, expected %s", other.Key(), c.Key())
+	}
+}
@@ -0,0 +1,79 @@
+package deck
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseError points at the part of the input that isn't a card.
+type ParseError struct {
+	Input string
+	// Position is where the problem is, counted in characters from 1.
+	Position int
+	Message  string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s at position %d of %q", e.Message, e.Position, e.Input)
+}
+
+var suitSymbols = map[rune]Suit{
+	'♣': Clubs, '♧': Clubs,
+	'♦': Diamonds, '♢': Diamonds,
+	'♥': Hearts, '♡': Hearts,
+	'♠': Spades, '♤': Spades,
+}
+
+// isSeparator tells the characters allowed between cards, as in "Ah Kd",
+// "Qs,7c,2d" or the hand history style "[Ah Kd]".
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ',' || r == '[' || r == ']'
+}
+
+// ParseHand reads any number of cards written together, such as "AhKd",
+// "Qs 7c 2d", "10h", "A♠" or "[Ah Kd]". Ranks and suits ignore case.
+func ParseHand(s string) ([]Card, error) {
+
+	runes := []rune(s)
+	cards := []Card{}
+
+	for i := 0; i < len(runes); {
+
+		if isSeparator(runes[i]) {
+			i++
+			continue
+		}
+
+		rankAt := i
+		var rank int
+		if runes[i] == '1' && i+1 < len(runes) && runes[i+1] == '0' {
+			rank = int(Ten)
+			i += 2
+		} else {
+			rank = strings.IndexRune(rankLetters, unicode.ToLower(runes[i]))
+			if rank < 0 {
+				return nil, &ParseError{s, rankAt + 1, fmt.Sprintf("%q is not a rank", runes[i])}
+			}
+			i++
+		}
+
+		if i == len(runes) {
+			return nil, &ParseError{s, i + 1, "missing suit"}
+		}
+
+		suit, ok := suitSymbols[runes[i]]
+		if !ok {
+			index := strings.IndexRune(suitLetters, unicode.ToLower(runes[i]))
+			if index < 0 {
+				return nil, &ParseError{s, i + 1, fmt.Sprintf("%q is not a suit", runes[i])}
+			}
+			suit = Suit(index)
+		}
+		i++
+
+		cards = append(cards, NewCard(Rank(rank), suit))
+	}
+
+	return cards, nil
+}
@@ -0,0 +1,88 @@
+package deck
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Shoe deals the cards of a deck in a random order decided by its seed. Two
+// shoes with the same deck and seed asked for the same removals, deals and
+// burns give the same cards, and Reset replays a shoe from the start.
+type Shoe struct {
+	deck  Deck
+	seed  int64
+	rng   *rand.Rand
+	cards []Card
+}
+
+func NewShoe(deck Deck, seed int64) *Shoe {
+	s := &Shoe{deck: deck, seed: seed}
+	s.Reset()
+	return s
+}
+
+func (s *Shoe) Seed() int64 {
+	return s.seed
+}
+
+// Reset puts every card back and restarts the random order from the seed.
+func (s *Shoe) Reset() {
+	s.rng = rand.New(rand.NewSource(s.seed))
+	s.cards = s.deck.Cards()
+}
+
+// Remove takes dead cards, such as known hands and boards, out of the shoe.
+// It fails without removing anything when a card isn't in the shoe, which
+// also catches a card given twice.
+func (s *Shoe) Remove(dead ...Card) error {
+
+	remaining := append([]Card(nil), s.cards...)
+
+	for _, c := range dead {
+		i := indexOf(remaining, c)
+		if i < 0 {
+			return fmt.Errorf("%s is not in the shoe", c)
+		}
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+
+	s.cards = remaining
+	return nil
+}
+
+// Deal returns n random cards and takes them out of the shoe.
+func (s *Shoe) Deal(n int) ([]Card, error) {
+
+	if n < 0 || n > len(s.cards) {
+		return nil, fmt.Errorf("cannot deal %d cards, %d remain", n, len(s.cards))
+	}
+
+	dealt := make([]Card, n)
+	for i := range dealt {
+		j := s.rng.Intn(len(s.cards))
+		dealt[i] = s.cards[j]
+		s.cards = append(s.cards[:j], s.cards[j+1:]...)
+	}
+
+	return dealt, nil
+}
+
+// Burn discards one random card.
+func (s *Shoe) Burn() error {
+	_, err := s.Deal(1)
+	return err
+}
+
+// Remaining returns the cards still in the shoe in deck order.
+func (s *Shoe) Remaining() []Card {
+	return append([]Card(nil), s.cards...)
+}
+
+func indexOf(cards []Card, c Card) int {
+	for i, dc := range cards {
+		if dc == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,142 @@
+package handevaluator
+
+import (
+	"math/bits"
+	"sort"
+)
+
+var primes = []uint32{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41}
+
+// fiveCardCombinations lists the ways of picking five of seven cards.
+var fiveCardCombinations = buildFiveCardCombinations()
+
+// CactusKevEvaluator evaluates hands the way Cactus Kev's evaluator does,
+// with a few kilobytes of tables instead of the Two Plus Two lookup table.
+// Hands of five distinct ranks are looked up by their rank bits, everything
+// else by the product of one prime per rank. Six and seven card hands are
+// the best of their five card hands.
+type CactusKevEvaluator struct {
+	cards    [53]uint32
+	flushes  []uint16
+	unique5  []uint16
+	products []uint32
+	values   []uint16
+}
+
+// NewCactusKev builds the tables of a CactusKevEvaluator.
+func NewCactusKev() *CactusKevEvaluator {
+
+	e := &CactusKevEvaluator{
+		flushes: make([]uint16, 1<<13),
+		unique5: make([]uint16, 1<<13),
+	}
+
+	// A card is its rank bit, suit bit, rank and prime:
+	// xxxbbbbb bbbbbbbb cdhsrrrr xxpppppp
+	for c := uint32(1); c <= 52; c++ {
+		rank, suit := (c-1)>>2, (c-1)&3
+		e.cards[c] = 1<<(16+rank) | 1<<(12+suit) | rank<<8 | primes[rank]
+	}
+
+	type productValue struct {
+		product uint32
+		value   uint16
+	}
+	paired := []productValue{}
+
+	forEachClass(func(counts *[13]uint8, mask uint16, suited bool) {
+		switch {
+		case suited:
+			e.flushes[mask] = uint16(classValues[bestStrength(counts, mask, mask)])
+		case bits.OnesCount16(mask) == 5:
+			e.unique5[mask] = uint16(classValues[bestStrength(counts, mask, 0)])
+		default:
+			product := uint32(1)
+			for r, n := range counts {
+				for i := uint8(0); i < n; i++ {
+					product *= primes[r]
+				}
+			}
+			paired = append(paired, productValue{product, uint16(classValues[bestStrength(counts, mask, 0)])})
+		}
+	})
+
+	sort.Slice(paired, func(i, j int) bool { return paired[i].product < paired[j].product })
+	e.products = make([]uint32, len(paired))
+	e.values = make([]uint16, len(paired))
+	for i, p := range paired {
+		e.products[i], e.values[i] = p.product, p.value
+	}
+
+	return e
+}
+
+func buildFiveCardCombinations() [][5]uint8 {
+	combinations := [][5]uint8{}
+	for a := uint8(0); a < 7; a++ {
+		for b := a + 1; b < 7; b++ {
+			for c := b + 1; c < 7; c++ {
+				for d := c + 1; d < 7; d++ {
+					for e := d + 1; e < 7; e++ {
+						combinations = append(combinations, [5]uint8{a, b, c, d, e})
+					}
+				}
+			}
+		}
+	}
+	return combinations
+}
+
+func (e *CactusKevEvaluator) eval5(c1, c2, c3, c4, c5 uint32) uint32 {
+
+	q := (c1 | c2 | c3 | c4 | c5) >> 16
+
+	if c1&c2&c3&c4&c5&0xf000 != 0 {
+		return uint32(e.flushes[q])
+	}
+
+	if v := e.unique5[q]; v != 0 {
+		return uint32(v)
+	}
+
+	product := (c1 & 0xff) * (c2 & 0xff) * (c3 & 0xff) * (c4 & 0xff) * (c5 & 0xff)
+	i := sort.Search(len(e.products), func(i int) bool { return e.products[i] >= product })
+	if i == len(e.products) || e.products[i] != product {
+		return 0
+	}
+	return uint32(e.values[i])
+}
+
+// Eval returns the value and hand type index of the best hand made from five,
+// six or seven cards. Any other number of cards is an invalid hand.
+func (e *CactusKevEvaluator) Eval(cards ...uint8) (uint32, uint32) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, InvalidHandIndex
+	}
+
+	var ck [7]uint32
+	for i, c := range cards {
+		if c < 1 || c > 52 {
+			return 0, InvalidHandIndex
+		}
+		ck[i] = e.cards[c]
+	}
+
+	best := uint32(0)
+	for _, combination := range fiveCardCombinations {
+		if int(combination[4]) >= len(cards) {
+			continue
+		}
+		v := e.eval5(ck[combination[0]], ck[combination[1]], ck[combination[2]], ck[combination[3]], ck[combination[4]])
+		if v > best {
+			best = v
+		}
+	}
+
+	return best, best >> handTypeShift
+}
+
+func (e *CactusKevEvaluator) PartialEvaluation(partial ...[]uint8) PartialEvaluation {
+	return newCardsPartialEvaluation(e, partial...)
+}
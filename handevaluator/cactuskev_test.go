@@ -0,0 +1,277 @@
+package handevaluator
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// The oracle ranks five card hands from the rules of poker alone, sharing no
+// code with the evaluators, so that a ranking bug in the code they share
+// can't hide.
+
+// oracleKey ranks five cards: the hand type, then the ranks that break ties
+// in order of significance, one hexadecimal digit each.
+func oracleKey(hand [5]uint8) (int, uint64) {
+
+	counts := [13]int{}
+	flush := true
+	for _, c := range hand {
+		counts[(c-1)/4]++
+		if (c-1)%4 != (hand[0]-1)%4 {
+			flush = false
+		}
+	}
+
+	// Ranks by how many cards of them there are, then by rank, best first.
+	ranks := []int{}
+	for r := 12; r >= 0; r-- {
+		if counts[r] > 0 {
+			ranks = append(ranks, r)
+		}
+	}
+	sort.SliceStable(ranks, func(i, j int) bool { return counts[ranks[i]] > counts[ranks[j]] })
+
+	straightTop := -1
+	if len(ranks) == 5 {
+		sorted := append([]int{}, ranks...)
+		sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+		switch {
+		case sorted[0]-sorted[4] == 4:
+			straightTop = sorted[0]
+		case sorted[0] == 12 && sorted[1] == 3:
+			// A-2-3-4-5, the five high straight.
+			straightTop = 3
+		}
+	}
+
+	var handType int
+	switch {
+	case straightTop >= 0 && flush:
+		handType, ranks = StraightFlush, []int{straightTop}
+	case counts[ranks[0]] == 4:
+		handType = FourOfAKind
+	case counts[ranks[0]] == 3 && counts[ranks[1]] == 2:
+		handType = FullHouse
+	case flush:
+		handType = Flush
+	case straightTop >= 0:
+		handType, ranks = Straight, []int{straightTop}
+	case counts[ranks[0]] == 3:
+		handType = ThreeOfAKind
+	case counts[ranks[0]] == 2 && counts[ranks[1]] == 2:
+		handType = TwoPairs
+	case counts[ranks[0]] == 2:
+		handType = OnePair
+	default:
+		handType = HighCard
+	}
+
+	key := uint64(handType)
+	for i := 0; i < 5; i++ {
+		key <<= 4
+		if i < len(ranks) {
+			key |= uint64(ranks[i])
+		}
+	}
+	return handType, key
+}
+
+// oracleValues numbers the distinct five card hands of every hand type from
+// 1, worst first, as the Two Plus Two values do.
+func oracleValues(t *testing.T) map[uint64]uint32 {
+
+	byType := map[int][]uint64{}
+	seen := map[uint64]struct{}{}
+
+	var hand [5]uint8
+	for a := uint8(1); a <= 52; a++ {
+		for b := a + 1; b <= 52; b++ {
+			for c := b + 1; c <= 52; c++ {
+				for d := c + 1; d <= 52; d++ {
+					for e := d + 1; e <= 52; e++ {
+						hand = [5]uint8{a, b, c, d, e}
+						handType, key := oracleKey(hand)
+						if _, ok := seen[key]; !ok {
+							seen[key] = struct{}{}
+							byType[handType] = append(byType[handType], key)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// The number of distinct hands of every type, as published.
+	distinct := map[int]int{
+		HighCard: 1277, OnePair: 2860, TwoPairs: 858, ThreeOfAKind: 858, Straight: 10,
+		Flush: 1277, FullHouse: 156, FourOfAKind: 156, StraightFlush: 10,
+	}
+
+	values := map[uint64]uint32{}
+	for handType, keys := range byType {
+		if len(keys) != distinct[handType] {
+			t.Fatalf("%s has %d distinct hands, expected %d", HandTypes()[handType], len(keys), distinct[handType])
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		for i, key := range keys {
+			values[key] = uint32(handType)<<handTypeShift | uint32(i+1)
+		}
+	}
+	return values
+}
+
+// oracleEval values five to seven cards as the best of their five card hands.
+func oracleEval(values map[uint64]uint32, cards []uint8) uint32 {
+
+	best := uint32(0)
+	for _, combination := range fiveCardCombinations {
+		if int(combination[4]) >= len(cards) {
+			continue
+		}
+		var hand [5]uint8
+		for i, index := range combination {
+			hand[i] = cards[index]
+		}
+		if _, key := oracleKey(hand); values[key] > best {
+			best = values[key]
+		}
+	}
+	return best
+}
+
+func TestFiveCardHandsMatchOracle(t *testing.T) {
+
+	values := oracleValues(t)
+	table := NewFromRanks(generatedRanks())
+	evaluators := map[string]Evaluator{"table": &table, "cactus kev": NewCactusKev()}
+
+	for a := uint8(1); a <= 52; a++ {
+		for b := a + 1; b <= 52; b++ {
+			for c := b + 1; c <= 52; c++ {
+				for d := c + 1; d <= 52; d++ {
+					for e := d + 1; e <= 52; e++ {
+						_, key := oracleKey([5]uint8{a, b, c, d, e})
+						for name, evaluator := range evaluators {
+							if value, _ := evaluator.Eval(a, b, c, d, e); value != values[key] {
+								t.Fatalf("%s values %v at %d, expected %d", name, []uint8{a, b, c, d, e}, value, values[key])
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSevenCardClassesMatchOracle(t *testing.T) {
+
+	values := oracleValues(t)
+	table := NewFromRanks(generatedRanks())
+
+	for name, evaluator := range map[string]Evaluator{"table": &table, "cactus kev": NewCactusKev()} {
+		if err := checkSevenCardClasses(evaluator, func(hand []uint8) uint32 { return oracleEval(values, hand) }); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// checkSevenCardClasses evaluates a hand of every seven card class and
+// reports the first one valued differently than expected. Every multiset of
+// seven ranks is dealt once without a flush and once for every way of
+// making a flush from five or more of its distinct ranks.
+func checkSevenCardClasses(evaluator Evaluator, expected func([]uint8) uint32) error {
+
+	counts := [13]uint8{}
+
+	check := func(hand []uint8) error {
+		value, _ := evaluator.Eval(hand...)
+		if want := expected(hand); value != want {
+			return fmt.Errorf("%v is valued %d, expected %d", hand, value, want)
+		}
+		return nil
+	}
+
+	var enumerate func(fromRank int, remaining int) error
+	enumerate = func(fromRank int, remaining int) error {
+		if remaining > 0 {
+			for r := fromRank; r < 13; r++ {
+				if counts[r] == 4 {
+					continue
+				}
+				counts[r]++
+				err := enumerate(r, remaining-1)
+				counts[r]--
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if err := check(dealWithoutFlush(&counts)); err != nil {
+			return err
+		}
+
+		mask := rankMaskOf(&counts)
+		for suited := mask; suited != 0; suited = (suited - 1) & mask {
+			if hand, ok := dealWithFlush(&counts, suited); ok {
+				if err := check(hand); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return enumerate(0, 7)
+}
+
+func card(rank int, suit int) uint8 {
+	return uint8(rank*4+suit) + 1
+}
+
+// dealWithoutFlush deals cards of the given ranks round robin over the suits,
+// which never puts more than two of seven cards in a suit.
+func dealWithoutFlush(counts *[13]uint8) []uint8 {
+	hand := []uint8{}
+	suit := 0
+	for r, n := range counts {
+		for i := uint8(0); i < n; i++ {
+			hand = append(hand, card(r, suit%4))
+			suit++
+		}
+	}
+	return hand
+}
+
+// dealWithFlush deals one spade of every rank in suited and the other cards
+// round robin over the remaining suits. It fails when suited can't be the
+// whole flush, because it has fewer than five ranks or leaves out quads.
+func dealWithFlush(counts *[13]uint8, suited uint16) ([]uint8, bool) {
+
+	hand := []uint8{}
+	suit := 0
+	flushCount := 0
+
+	for r, n := range counts {
+		if n == 0 {
+			continue
+		}
+		offsuit := n
+		if suited&(1<<uint(r)) != 0 {
+			hand = append(hand, card(r, 3))
+			offsuit--
+			flushCount++
+		}
+		if offsuit > 3 {
+			return nil, false
+		}
+		for i := uint8(0); i < offsuit; i++ {
+			hand = append(hand, card(r, suit%3))
+			suit++
+		}
+	}
+
+	return hand, flushCount >= 5
+}
@@ -0,0 +1,192 @@
+package handevaluator
+
+import (
+	"fmt"
+	"holdem/deck"
+	"math/bits"
+	"strings"
+)
+
+// EquivalenceClassCount is the number of distinct five card poker hands.
+const EquivalenceClassCount = 7462
+
+// handTypeSizes holds the number of distinct hands of each hand type.
+var handTypeSizes = []int{0, 1277, 2860, 858, 858, 10, 1277, 156, 156, 10}
+
+var rankNames = []string{"Two", "Three", "Four", "Five", "Six", "Seven", "Eight", "Nine", "Ten", "Jack", "Queen", "King", "Ace"}
+var pluralRankNames = []string{"Twos", "Threes", "Fours", "Fives", "Sixes", "Sevens", "Eights", "Nines", "Tens", "Jacks", "Queens", "Kings", "Aces"}
+
+// HandDescription explains the best five card hand made from a set of cards.
+type HandDescription struct {
+	Value    uint32
+	HandType uint32
+	// EquivalenceClass ranks the hand from 1, a royal flush, to 7462, seven
+	// high.
+	EquivalenceClass int
+	// BestCards are the five cards making the hand, most significant first.
+	BestCards []deck.Card
+	Text      string
+}
+
+// EquivalenceClass converts a hand value into its rank among all distinct
+// five card hands, from 1 for a royal flush to 7462 for seven high.
+func EquivalenceClass(value uint32) int {
+
+	handType := int(value >> handTypeShift)
+	if handType == InvalidHandIndex || handType >= len(handTypeSizes) {
+		return 0
+	}
+
+	weaker := int(value & (1<<handTypeShift - 1))
+	for _, size := range handTypeSizes[:handType] {
+		weaker += size
+	}
+
+	return EquivalenceClassCount + 1 - weaker
+}
+
+// Describe finds the best five card hand among five to seven cards.
+func Describe(cards []deck.Card) (HandDescription, error) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return HandDescription{}, fmt.Errorf("please provide 5, 6 or 7 cards")
+	}
+
+	counts := [13]uint8{}
+	suitMasks := [4]uint16{}
+
+	for _, c := range cards {
+		if !c.Valid() {
+			return HandDescription{}, fmt.Errorf("%d is not a valid card", uint8(c))
+		}
+		rank, suit := c.Rank(), c.Suit()
+		if suitMasks[suit]&(1<<rank) != 0 {
+			return HandDescription{}, fmt.Errorf("found more than one %s", c)
+		}
+		counts[rank]++
+		suitMasks[suit] |= 1 << rank
+	}
+
+	flushSuit := -1
+	flushMask := uint16(0)
+	for suit, m := range suitMasks {
+		if bits.OnesCount16(m) >= 5 {
+			flushSuit, flushMask = suit, m
+		}
+	}
+
+	key := bestStrength(&counts, rankMaskOf(&counts), flushMask)
+	value := classValues[key]
+
+	return HandDescription{
+		Value:            value,
+		HandType:         key.handType(),
+		EquivalenceClass: EquivalenceClass(value),
+		BestCards:        bestCards(cards, key, flushSuit),
+		Text:             describeStrength(key),
+	}, nil
+}
+
+// significantRanks unpacks the ranks stored in a strength key.
+func (k strengthKey) significantRanks() []int {
+	ranks := make([]int, 5)
+	for i := range ranks {
+		ranks[i] = int(k>>(16-4*uint(i))) & 0xf
+	}
+	return ranks
+}
+
+// handShape returns the ranks of the five cards making a hand, most
+// significant first, given its strength.
+func (k strengthKey) handShape() []int {
+
+	r := k.significantRanks()
+
+	switch k.handType() {
+	case StraightFlush, Straight:
+		shape := make([]int, 5)
+		for i := range shape {
+			shape[i] = r[0] - i
+		}
+		if r[0] == 3 {
+			shape[4] = 12
+		}
+		return shape
+	case FourOfAKind:
+		return []int{r[0], r[0], r[0], r[0], r[1]}
+	case FullHouse:
+		return []int{r[0], r[0], r[0], r[1], r[1]}
+	case ThreeOfAKind:
+		return []int{r[0], r[0], r[0], r[1], r[2]}
+	case TwoPairs:
+		return []int{r[0], r[0], r[1], r[1], r[2]}
+	case OnePair:
+		return []int{r[0], r[0], r[1], r[2], r[3]}
+	default:
+		return r
+	}
+}
+
+func bestCards(cards []deck.Card, key strengthKey, flushSuit int) []deck.Card {
+
+	suited := key.handType() == StraightFlush || key.handType() == Flush
+	used := make([]bool, len(cards))
+	best := make([]deck.Card, 0, 5)
+
+	for _, rank := range key.handShape() {
+		for i, c := range cards {
+			if used[i] || int(c.Rank()) != rank || (suited && int(c.Suit()) != flushSuit) {
+				continue
+			}
+			used[i] = true
+			best = append(best, c)
+			break
+		}
+	}
+
+	return best
+}
+
+func kickers(ranks []int) string {
+
+	names := make([]string, len(ranks))
+	for i, r := range ranks {
+		names[i] = rankNames[r]
+	}
+
+	if len(ranks) == 1 {
+		return names[0] + " kicker"
+	}
+	return strings.Join(names, " ") + " kickers"
+}
+
+// describeStrength names a hand the way a dealer would call it, for example
+// "Two pair, Kings and Sevens, Ace kicker".
+func describeStrength(k strengthKey) string {
+
+	r := k.significantRanks()
+
+	switch k.handType() {
+	case StraightFlush:
+		if r[0] == 12 {
+			return "Royal flush"
+		}
+		return fmt.Sprintf("Straight flush, %s high", rankNames[r[0]])
+	case FourOfAKind:
+		return fmt.Sprintf("Four of a kind, %s, %s", pluralRankNames[r[0]], kickers(r[1:2]))
+	case FullHouse:
+		return fmt.Sprintf("Full house, %s full of %s", pluralRankNames[r[0]], pluralRankNames[r[1]])
+	case Flush:
+		return fmt.Sprintf("Flush, %s high, %s", rankNames[r[0]], kickers(r[1:]))
+	case Straight:
+		return fmt.Sprintf("Straight, %s high", rankNames[r[0]])
+	case ThreeOfAKind:
+		return fmt.Sprintf("Three of a kind, %s, %s", pluralRankNames[r[0]], kickers(r[1:3]))
+	case TwoPairs:
+		return fmt.Sprintf("Two pair, %s and %s, %s", pluralRankNames[r[0]], pluralRankNames[r[1]], kickers(r[2:3]))
+	case OnePair:
+		return fmt.Sprintf("Pair of %s, %s", pluralRankNames[r[0]], kickers(r[1:4]))
+	default:
+		return fmt.Sprintf("%s high, %s", rankNames[r[0]], kickers(r[1:]))
+	}
+}
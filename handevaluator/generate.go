@@ -0,0 +1,187 @@
+package handevaluator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// The lookup table is a state machine. Every state is a partial hand of up to
+// six cards and owns 53 consecutive entries: the entry at offset 0 holds the
+// value of the state's own hand once it has five or six cards, and the entry
+// at offset c (1-52) holds the state reached by adding card c, or the final
+// hand value when that card completes a seven card hand.
+//
+// A state is identified by its cards packed one per byte, highest first, with
+// the rank (1-13) in the high nibble and the suit (1-4) in the low nibble.
+// Suits that can no longer make a flush by the seventh card are zeroed, which
+// is what keeps the number of states small.
+
+const statesPerEntry = 53
+
+// TableSize is the number of entries in the Two Plus Two lookup table.
+const TableSize = 32487834
+
+// addCard returns the state reached by adding card to state together with the
+// number of cards in it. A zero state means the card can't be added.
+func addCard(state uint64, card uint8) (uint64, int) {
+
+	suitCounts := [5]int{}
+	rankCounts := [14]int{}
+	work := [8]uint8{}
+
+	for i := 0; i < 6; i++ {
+		work[i+1] = uint8(state >> (8 * uint(i)))
+	}
+	card--
+	work[0] = ((card>>2)+1)<<4 | ((card & 3) + 1)
+
+	cardCount := 0
+	duplicate := false
+	for ; work[cardCount] != 0; cardCount++ {
+		suitCounts[work[cardCount]&0xf]++
+		rankCounts[work[cardCount]>>4]++
+		if cardCount > 0 && work[0] == work[cardCount] {
+			duplicate = true
+		}
+	}
+
+	if duplicate {
+		return 0, cardCount
+	}
+
+	if cardCount > 4 {
+		for _, n := range rankCounts {
+			if n > 4 {
+				return 0, cardCount
+			}
+		}
+	}
+
+	if needSuited := cardCount - 2; needSuited > 1 {
+		for i := 0; i < cardCount; i++ {
+			if suitCounts[work[i]&0xf] < needSuited {
+				work[i] &= 0xf0
+			}
+		}
+	}
+
+	for i := 1; i < cardCount; i++ {
+		for j := i; j > 0 && work[j] > work[j-1]; j-- {
+			work[j], work[j-1] = work[j-1], work[j]
+		}
+	}
+
+	var next uint64
+	for i, c := range work[:cardCount] {
+		next |= uint64(c) << (8 * uint(i))
+	}
+	return next, cardCount
+}
+
+// evaluateState ranks the five to seven cards of a state. Cards whose suit
+// was dropped can't be part of a flush.
+func evaluateState(state uint64) uint32 {
+
+	if state == 0 {
+		return 0
+	}
+
+	counts := [13]uint8{}
+	suitMasks := [5]uint16{}
+
+	for ; state != 0; state >>= 8 {
+		rank, suit := uint8(state>>4)&0xf-1, uint8(state)&0xf
+		counts[rank]++
+		suitMasks[suit] |= 1 << rank
+	}
+
+	flushMask := uint16(0)
+	for _, m := range suitMasks[1:] {
+		if bits.OnesCount16(m) >= 5 {
+			flushMask = m
+		}
+	}
+
+	return classValues[bestStrength(&counts, rankMaskOf(&counts), flushMask)]
+}
+
+func allStates() []uint64 {
+
+	seen := map[uint64]struct{}{0: {}}
+	states := []uint64{0}
+
+	for frontier := states; len(frontier) > 0; {
+		next := []uint64{}
+		for _, state := range frontier {
+			for card := uint8(1); card <= 52; card++ {
+				s, cardCount := addCard(state, card)
+				if s == 0 || cardCount == 7 {
+					continue
+				}
+				if _, ok := seen[s]; !ok {
+					seen[s] = struct{}{}
+					next = append(next, s)
+				}
+			}
+		}
+		states = append(states, next...)
+		frontier = next
+	}
+
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+	return states
+}
+
+// Generate builds the Two Plus Two lookup table, entry for entry the same as
+// the one distributed as HandRanks.dat.
+func Generate() []uint32 {
+
+	states := allStates()
+	stateIndexes := make(map[uint64]uint32, len(states))
+	for i, s := range states {
+		stateIndexes[s] = uint32(i)
+	}
+
+	ranks := make([]uint32, len(states)*statesPerEntry+statesPerEntry)
+
+	for i, state := range states {
+		offset := i*statesPerEntry + statesPerEntry
+		cardCount := 0
+
+		for card := uint8(1); card <= 52; card++ {
+			var next uint64
+			next, cardCount = addCard(state, card)
+
+			if cardCount < 7 {
+				ranks[offset+int(card)] = stateIndexes[next]*statesPerEntry + statesPerEntry
+			} else {
+				ranks[offset+int(card)] = evaluateState(next)
+			}
+		}
+
+		if cardCount == 6 || cardCount == 7 {
+			ranks[offset] = evaluateState(state)
+		}
+	}
+
+	return ranks
+}
+
+// WriteTable writes ranks in the little endian layout of HandRanks.dat.
+func WriteTable(w io.Writer, ranks []uint32) error {
+
+	buffered := bufio.NewWriter(w)
+	entry := make([]byte, 4)
+
+	for _, r := range ranks {
+		binary.LittleEndian.PutUint32(entry, r)
+		if _, err := buffered.Write(entry); err != nil {
+			return err
+		}
+	}
+
+	return buffered.Flush()
+}
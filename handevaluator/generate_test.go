@@ -0,0 +1,118 @@
+package handevaluator
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var (
+	generateOnce sync.Once
+	generated    []uint32
+)
+
+// generatedRanks generates the table once for every test that needs it.
+func generatedRanks() []uint32 {
+	generateOnce.Do(func() {
+		generated = Generate()
+	})
+	return generated
+}
+
+func TestGeneratedTableRoundTrips(t *testing.T) {
+
+	ranks := generatedRanks()
+
+	if checksum := tableChecksum(ranks); checksum != StandardChecksum {
+		t.Fatalf("generated table checksum is %#08x, expected %#08x", checksum, StandardChecksum)
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteTable(&buffer, ranks); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewFromReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(loaded.ranks) != len(ranks) {
+		t.Fatalf("loaded %d entries, generated %d", len(loaded.ranks), len(ranks))
+	}
+	for i, r := range ranks {
+		if loaded.ranks[i] != r {
+			t.Fatalf("entry %d: loaded %d, generated %d", i, loaded.ranks[i], r)
+		}
+	}
+
+	fromMemory := NewFromRanks(ranks)
+	rGen := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		deck := rGen.Perm(52)
+		hand := make([]uint8, 5+i%3)
+		for j := range hand {
+			hand[j] = uint8(deck[j] + 1)
+		}
+
+		value, handType := fromMemory.Eval(hand...)
+		loadedValue, loadedHandType := loaded.Eval(hand...)
+		if value != loadedValue || handType != loadedHandType {
+			t.Fatalf("%v: generated table gives %d, loaded table %d", hand, value, loadedValue)
+		}
+	}
+}
+
+// TestGeneratedTableMatchesPublished pins the generated table to the
+// published HandRanks.dat: its size of 129951336 bytes, its checksum, and
+// values of the Two Plus Two format, hand type << 12 | rank within type.
+func TestGeneratedTableMatchesPublished(t *testing.T) {
+
+	ranks := generatedRanks()
+
+	if len(ranks) != 32487834 {
+		t.Errorf("generated %d entries, the published table has 32487834", len(ranks))
+	}
+	if checksum := tableChecksum(ranks); checksum != 0x7808da57 {
+		t.Errorf("generated table checksum is %#08x, the published table's is 0x7808da57", checksum)
+	}
+
+	table := NewFromRanks(ranks)
+	published := []struct {
+		name  string
+		hand  []uint8
+		value uint32
+	}{
+		{"7-5-4-3-2", []uint8{21, 14, 9, 5, 1}, 4097},
+		{"A-K-Q-J-9", []uint8{52, 47, 42, 37, 29}, 5373},
+		{"pair of deuces, 5-4-3", []uint8{1, 2, 13, 9, 6}, 8193},
+		{"four aces, king", []uint8{49, 50, 51, 52, 45}, 32924},
+		{"steel wheel", []uint8{52, 16, 12, 8, 4}, 36865},
+		{"royal flush", []uint8{52, 48, 44, 40, 36}, 36874},
+		{"royal flush and two deuces", []uint8{52, 48, 44, 40, 36, 1, 2}, 36874},
+	}
+	for _, p := range published {
+		if value, _ := table.Eval(p.hand...); value != p.value {
+			t.Errorf("%s is valued %d, published %d", p.name, value, p.value)
+		}
+	}
+
+	// A published table next to the module is compared entry for entry.
+	path := filepath.Join("..", DefaultTablePath)
+	if _, err := os.Stat(path); err != nil {
+		t.Logf("no %s to compare with", path)
+		return
+	}
+	loaded, err := NewFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range ranks {
+		if loaded.ranks[i] != r {
+			t.Fatalf("entry %d: %s has %d, generated %d", i, path, loaded.ranks[i], r)
+		}
+	}
+}
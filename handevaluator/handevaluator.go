@@ -1,18 +1,44 @@
 package handevaluator
 
 import (
-	"encoding/binary"
+	"fmt"
+	"holdem/deck"
 	"os"
 )
 
 type EvaluatedHand struct {
 	HandName string
 	//HandRank uint32
-	Value uint32
+	Value            uint32
+	EquivalenceClass int
+	BestCards        []deck.Card
+	Description      string
 }
 
+// Evaluator ranks hands of cards numbered the way deck.Card numbers them;
+// higher values are better hands. The high hand evaluators all return the
+// same values, hand type << 12 | rank within the hand type, so values from
+// different high evaluators can be compared. Low and short deck evaluators
+// have values of their own.
+type Evaluator interface {
+	// Eval returns the value and hand type index of the best hand made from
+	// five, six or seven cards.
+	Eval(cards ...uint8) (uint32, uint32)
+	// PartialEvaluation starts the evaluation of three to five known cards
+	// that will be completed by different pairs of cards.
+	PartialEvaluation(partial ...[]uint8) PartialEvaluation
+}
+
+// PartialEvaluation completes an evaluation started by
+// Evaluator.PartialEvaluation with two more cards.
+type PartialEvaluation interface {
+	Eval(a uint8, b uint8) (uint32, uint32)
+}
+
+// HandEvaluator is the Two Plus Two lookup table evaluator.
 type HandEvaluator struct {
 	ranks []uint32
+	unmap func() error
 	//handTypes []string
 }
 
@@ -33,10 +59,18 @@ func HandTypes() []string {
 	}
 }
 
+// DefaultTablePath is where New looks for the lookup table.
+const DefaultTablePath = "HandRanks.dat"
+
+// New loads DefaultTablePath from the working directory, generating the
+// table when the file doesn't exist.
 func New() (HandEvaluator, error) {
-	h := HandEvaluator{}
-	//h.handTypes = HandTypes()
-	err := h.intializeBuffer()
+	h, err := NewFromFile(DefaultTablePath)
+
+	if os.IsNotExist(err) {
+		fmt.Println(DefaultTablePath + " not found, generating hand ranks")
+		return NewFromRanks(Generate()), nil
+	}
 
 	if err != nil {
 		return h, err
@@ -44,61 +78,99 @@ func New() (HandEvaluator, error) {
 	return h, nil
 }
 
-func (e *HandEvaluator) intializeBuffer() error {
-	file, err := os.Open("HandRanks.dat")
-	if err != nil {
-		return err
-	}
-
-	defer file.Close()
-
-	fileinfo, err := file.Stat()
-	if err != nil {
-		return err
-	}
-
-	filesize := fileinfo.Size()
-	buffer := make([]byte, filesize)
-	e.ranks = make([]uint32, filesize/4)
-
-	_, err = file.Read(buffer)
-	if err != nil {
-		return err
+// Close releases the memory mapping of an evaluator made by NewMapped. The
+// evaluator and any copies of it can't be used afterwards.
+func (e *HandEvaluator) Close() error {
+	if e.unmap == nil {
+		return nil
 	}
 
-	for i := 0; i < int(filesize); i += 4 {
-		e.ranks[i/4] = binary.LittleEndian.Uint32(buffer[i:])
-	}
-	return nil
+	err := e.unmap()
+	e.unmap = nil
+	e.ranks = nil
+	return err
 }
 
-func (e *PartialEvaluation) fromBuffer(p uint32, c uint8) uint32 {
+func (e *tablePartialEvaluation) fromBuffer(p uint32, c uint8) uint32 {
 	return e.ranks[p+uint32(c)]
 
 }
 
 func (e *HandEvaluator) PartialEvaluation(partial ...[]uint8) PartialEvaluation {
 
-	partialEvaluation := PartialEvaluation{ranks: e.ranks}
+	partialEvaluation := &tablePartialEvaluation{ranks: e.ranks}
 	var partialResult uint32 = 53
 	for _, subset := range partial {
 		for _, c := range subset {
 			partialResult = partialEvaluation.fromBuffer(partialResult, c)
 		}
+		partialEvaluation.cardCount += len(subset)
 	}
 
 	partialEvaluation.partial = partialResult
 	return partialEvaluation
 }
 
-type PartialEvaluation struct {
-	partial uint32
-	ranks   []uint32
+// Eval returns the value and hand type index of the best hand made from five,
+// six or seven cards. Any other number of cards is an invalid hand.
+func (e *HandEvaluator) Eval(cards ...uint8) (uint32, uint32) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, InvalidHandIndex
+	}
+
+	var result uint32 = 53
+	for _, c := range cards {
+		result = e.ranks[result+uint32(c)]
+	}
+
+	// Hands of five and six cards stop at a state rather than a value, the
+	// value of a state's own hand is its first entry.
+	if len(cards) < 7 {
+		result = e.ranks[result]
+	}
+
+	return result, result >> handTypeShift
+}
+
+type tablePartialEvaluation struct {
+	partial   uint32
+	cardCount int
+	ranks     []uint32
 }
 
-func (e *PartialEvaluation) Eval(a uint8, b uint8) (uint32, uint32) {
+func (e *tablePartialEvaluation) Eval(a uint8, b uint8) (uint32, uint32) {
 
 	finalResult := e.fromBuffer(e.fromBuffer(e.partial, a), b)
 
+	if e.cardCount < 5 {
+		finalResult = e.ranks[finalResult]
+	}
+
 	return finalResult, finalResult >> 12
 }
+
+// cardsPartialEvaluation keeps the known cards of a partial evaluation and
+// evaluates them again with every pair, for evaluators without a cheaper way.
+type cardsPartialEvaluation struct {
+	evaluator Evaluator
+	cards     []uint8
+}
+
+func newCardsPartialEvaluation(evaluator Evaluator, partial ...[]uint8) *cardsPartialEvaluation {
+
+	partialEvaluation := &cardsPartialEvaluation{evaluator: evaluator}
+	for _, subset := range partial {
+		partialEvaluation.cards = append(partialEvaluation.cards, subset...)
+	}
+	return partialEvaluation
+}
+
+func (e *cardsPartialEvaluation) Eval(a uint8, b uint8) (uint32, uint32) {
+
+	var hand [7]uint8
+	n := copy(hand[:5], e.cards)
+	hand[n], hand[n+1] = a, b
+
+	return e.evaluator.Eval(hand[:n+2]...)
+}
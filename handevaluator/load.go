@@ -0,0 +1,98 @@
+package handevaluator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+const readChunkSize = 1 << 16
+
+// NewFromFile loads the lookup table stored at path. Like all the loaders it
+// rejects tables that are truncated, corrupted or of an unknown version.
+func NewFromFile(path string) (HandEvaluator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	defer file.Close()
+	return newFromOpenFile(file)
+}
+
+// NewFromFS loads the lookup table stored as name in fsys, for example a
+// table embedded in a binary.
+func NewFromFS(fsys fs.FS, name string) (HandEvaluator, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	defer file.Close()
+	return newFromOpenFile(file)
+}
+
+// NewFromReader loads the lookup table from r, reading until EOF.
+func NewFromReader(r io.Reader) (HandEvaluator, error) {
+	return newFromRanks(readRanks(r, TableSize))
+}
+
+func newFromOpenFile(file fs.File) (HandEvaluator, error) {
+	fileinfo, err := file.Stat()
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	return newFromRanks(readRanks(file, int(fileinfo.Size()/4)))
+}
+
+// NewFromRanks wraps a table built in memory, such as the one returned by
+// Generate.
+func NewFromRanks(ranks []uint32) HandEvaluator {
+	return HandEvaluator{ranks: ranks}
+}
+
+func newFromRanks(ranks []uint32, err error) (HandEvaluator, error) {
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	ranks, err = checkTable(ranks)
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+	return HandEvaluator{ranks: ranks}, nil
+}
+
+// readRanks decodes little endian entries from r straight into the table, so
+// loading needs no more memory than the table itself.
+func readRanks(r io.Reader, expectedEntries int) ([]uint32, error) {
+
+	ranks := make([]uint32, 0, expectedEntries)
+	buffer := make([]byte, readChunkSize)
+	pending := 0
+
+	for {
+		n, err := r.Read(buffer[pending:])
+		n += pending
+		whole := n - n%4
+
+		for i := 0; i < whole; i += 4 {
+			ranks = append(ranks, binary.LittleEndian.Uint32(buffer[i:]))
+		}
+		pending = copy(buffer, buffer[whole:n])
+
+		if err == io.EOF {
+			if pending != 0 {
+				return nil, fmt.Errorf("hand ranks table ends with a partial entry of %d bytes", pending)
+			}
+			return ranks, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
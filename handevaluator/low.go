@@ -0,0 +1,250 @@
+package handevaluator
+
+import "math/bits"
+
+// lowValueLimit turns strengths, where lower is a better low, into low
+// values, where higher is better like every other evaluator's values.
+const lowValueLimit = 1 << 24
+
+// eightRank is the eight's rank when aces are low.
+const eightRank = 7
+
+// AceToFiveEvaluator ranks low hands the A-5 way used in Razz and split pot
+// games: aces are low and straights and flushes don't count against a hand.
+// Higher values are better lows. Evaluated for eight or better, hands without
+// five different ranks of eight or lower don't qualify and are worth 0.
+type AceToFiveEvaluator struct {
+	eightOrBetter bool
+}
+
+func NewAceToFive() AceToFiveEvaluator {
+	return AceToFiveEvaluator{}
+}
+
+func NewEightOrBetter() AceToFiveEvaluator {
+	return AceToFiveEvaluator{eightOrBetter: true}
+}
+
+// aceLowRank moves the ace below the deuce.
+func aceLowRank(c uint8) uint8 {
+	return ((c-1)>>2 + 1) % 13
+}
+
+// Eval returns the low value and hand type index of the best low made from
+// five, six or seven cards.
+func (e AceToFiveEvaluator) Eval(cards ...uint8) (uint32, uint32) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, InvalidHandIndex
+	}
+
+	counts := [13]uint8{}
+	for _, c := range cards {
+		counts[aceLowRank(c)]++
+	}
+	mask := rankMaskOf(&counts)
+
+	if e.eightOrBetter {
+		return eightOrBetterValue(mask)
+	}
+
+	var key strengthKey
+	switch {
+	case bits.OnesCount16(mask) >= 5:
+		key = highCardKey(lowestFive(mask))
+	case len(cards) == 5:
+		key = pairedStrength(&counts, mask)
+	default:
+		key = lowestOfFive(cards)
+	}
+
+	return lowValueLimit - uint32(key), key.handType()
+}
+
+// eightOrBetterValue returns the best eight or better low among the ace low
+// ranks in mask.
+func eightOrBetterValue(mask uint16) (uint32, uint32) {
+
+	mask &= 1<<(eightRank+1) - 1
+	if bits.OnesCount16(mask) < 5 {
+		return 0, InvalidHandIndex
+	}
+
+	return lowValueLimit - uint32(highCardKey(lowestFive(mask))), HighCard
+}
+
+// lowestFive keeps the five lowest ranks of mask.
+func lowestFive(mask uint16) uint16 {
+	lowest := uint16(0)
+	for i := 0; i < 5; i++ {
+		lowest |= mask & -mask
+		mask &= mask - 1
+	}
+	return lowest
+}
+
+// highCardKey is the strength of five different ranks.
+func highCardKey(mask uint16) strengthKey {
+	key := strengthKey(HighCard) << 20
+	shift := uint(16)
+	for r := 12; r >= 0; r-- {
+		if mask&(1<<uint(r)) != 0 {
+			key |= strengthKey(r) << shift
+			shift -= 4
+		}
+	}
+	return key
+}
+
+func (e AceToFiveEvaluator) PartialEvaluation(partial ...[]uint8) PartialEvaluation {
+	return newCardsPartialEvaluation(e, partial...)
+}
+
+// lowestOfFive returns the best A-5 low among every five of six or seven
+// cards.
+func lowestOfFive(cards []uint8) strengthKey {
+
+	lowest := strengthKey(lowValueLimit)
+
+	for _, combination := range fiveCardCombinations {
+		if int(combination[4]) >= len(cards) {
+			continue
+		}
+
+		counts := [13]uint8{}
+		for _, i := range combination {
+			counts[aceLowRank(cards[i])]++
+		}
+
+		if key := pairedStrength(&counts, rankMaskOf(&counts)); key < lowest {
+			lowest = key
+		}
+	}
+
+	return lowest
+}
+
+// DeuceToSevenEvaluator ranks low hands the 2-7 way used in draw games: aces
+// are only high, and straights and flushes count against a hand, so the best
+// low is 7-5-4-3-2 unsuited. Higher values are better lows.
+type DeuceToSevenEvaluator struct{}
+
+func NewDeuceToSeven() DeuceToSevenEvaluator {
+	return DeuceToSevenEvaluator{}
+}
+
+// Eval returns the low value and the hand type index of the best 2-7 low made
+// from five, six or seven cards. The hand type is that of the hand as a high
+// hand, such as a pair.
+func (e DeuceToSevenEvaluator) Eval(cards ...uint8) (uint32, uint32) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, InvalidHandIndex
+	}
+
+	lowest := strengthKey(lowValueLimit)
+	var five [5]uint8
+
+	for _, combination := range fiveCardCombinations {
+		if int(combination[4]) >= len(cards) {
+			continue
+		}
+
+		for i, c := range combination {
+			five[i] = cards[c]
+		}
+
+		if key := deuceToSevenStrength(&five); key < lowest {
+			lowest = key
+		}
+	}
+
+	return lowValueLimit - uint32(lowest), lowest.handType()
+}
+
+// deuceToSevenStrength is the high hand strength of five cards, except that
+// A-2-3-4-5 is ace high rather than a straight.
+func deuceToSevenStrength(cards *[5]uint8) strengthKey {
+
+	counts := [13]uint8{}
+	suits := uint8(0)
+	for _, c := range cards {
+		counts[(c-1)>>2]++
+		suits |= 1 << ((c - 1) & 3)
+	}
+	mask := rankMaskOf(&counts)
+
+	flushMask := uint16(0)
+	if bits.OnesCount8(suits) == 1 {
+		flushMask = mask
+	}
+
+	if mask == wheelMask {
+		if flushMask != 0 {
+			return newStrengthKey(Flush, topRanks(mask, 5)...)
+		}
+		return pairedStrength(&counts, mask)
+	}
+
+	return bestStrength(&counts, mask, flushMask)
+}
+
+func (e DeuceToSevenEvaluator) PartialEvaluation(partial ...[]uint8) PartialEvaluation {
+	return newCardsPartialEvaluation(e, partial...)
+}
+
+// OmahaLowBoard evaluates the eight or better lows of any number of Omaha
+// hands against one board.
+type OmahaLowBoard struct {
+	// triplets are the ace low rank masks of every three different board
+	// cards of eight or lower.
+	triplets []uint16
+}
+
+// OmahaBoard prepares the evaluation of Omaha lows against three to five
+// board cards.
+func (e AceToFiveEvaluator) OmahaBoard(board []uint8) OmahaLowBoard {
+
+	lowBoard := OmahaLowBoard{}
+
+	for b1 := 0; b1 < len(board); b1++ {
+		for b2 := b1 + 1; b2 < len(board); b2++ {
+			for b3 := b2 + 1; b3 < len(board); b3++ {
+				triplet := uint16(1)<<aceLowRank(board[b1]) | 1<<aceLowRank(board[b2]) | 1<<aceLowRank(board[b3])
+				if bits.OnesCount16(triplet) == 3 && triplet < 1<<(eightRank+1) {
+					lowBoard.triplets = append(lowBoard.triplets, triplet)
+				}
+			}
+		}
+	}
+
+	return lowBoard
+}
+
+// Eval returns the low value and hand type index of the best low made from
+// two of the hole cards and three of the board, 0 when there is none.
+func (b OmahaLowBoard) Eval(hole []uint8) (uint32, uint32) {
+
+	// Comparing the masks of five different ranks compares the lows, the
+	// lower mask is the better low.
+	best := uint16(0xffff)
+
+	for h1 := 0; h1 < len(hole); h1++ {
+		for h2 := h1 + 1; h2 < len(hole); h2++ {
+			pair := uint16(1)<<aceLowRank(hole[h1]) | 1<<aceLowRank(hole[h2])
+			if bits.OnesCount16(pair) != 2 || pair >= 1<<(eightRank+1) {
+				continue
+			}
+			for _, triplet := range b.triplets {
+				if pair&triplet == 0 && pair|triplet < best {
+					best = pair | triplet
+				}
+			}
+		}
+	}
+
+	if best == 0xffff {
+		return 0, InvalidHandIndex
+	}
+	return eightOrBetterValue(best)
+}
@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package handevaluator
+
+// NewMapped reads the lookup table at path, memory mapping isn't supported
+// on this platform.
+func NewMapped(path string) (HandEvaluator, error) {
+	return NewFromFile(path)
+}
@@ -0,0 +1,59 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package handevaluator
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// NewMapped maps the lookup table at path read-only into memory instead of
+// copying it, so processes on the same host share one page cached table.
+// Close releases the mapping. On big endian hosts the table is read instead.
+func NewMapped(path string) (HandEvaluator, error) {
+
+	if !isLittleEndian() {
+		return NewFromFile(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	defer file.Close()
+
+	fileinfo, err := file.Stat()
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	size := fileinfo.Size()
+	if size == 0 || size%4 != 0 {
+		return HandEvaluator{}, fmt.Errorf("%s is %d bytes, not a whole number of entries", path, size)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return HandEvaluator{}, err
+	}
+
+	ranks, err := checkTable(unsafe.Slice((*uint32)(unsafe.Pointer(&data[0])), len(data)/4))
+	if err != nil {
+		syscall.Munmap(data)
+		return HandEvaluator{}, err
+	}
+
+	return HandEvaluator{
+		ranks: ranks,
+		unmap: func() error { return syscall.Munmap(data) },
+	}, nil
+}
+
+func isLittleEndian() bool {
+	probe := uint16(1)
+	return *(*byte)(unsafe.Pointer(&probe)) == 1
+}
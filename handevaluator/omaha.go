@@ -0,0 +1,63 @@
+package handevaluator
+
+// OmahaEvaluator evaluates Omaha hands, which have to use exactly two of
+// their hole cards and exactly three cards of the board.
+type OmahaEvaluator struct {
+	evaluator Evaluator
+}
+
+func NewOmaha(evaluator Evaluator) OmahaEvaluator {
+	return OmahaEvaluator{evaluator: evaluator}
+}
+
+// OmahaBoard evaluates any number of Omaha hands against one board, starting
+// from the evaluations of every three cards of the board.
+type OmahaBoard struct {
+	triplets []PartialEvaluation
+}
+
+// Board prepares the evaluation of hands against three to five board cards.
+func (o OmahaEvaluator) Board(board []uint8) OmahaBoard {
+
+	omahaBoard := OmahaBoard{}
+
+	for b1 := 0; b1 < len(board); b1++ {
+		for b2 := b1 + 1; b2 < len(board); b2++ {
+			for b3 := b2 + 1; b3 < len(board); b3++ {
+				omahaBoard.triplets = append(omahaBoard.triplets,
+					o.evaluator.PartialEvaluation([]uint8{board[b1], board[b2], board[b3]}))
+			}
+		}
+	}
+
+	return omahaBoard
+}
+
+// Eval returns the value and hand type index of the best hand made from two
+// of the hole cards and three of the board.
+func (b OmahaBoard) Eval(hole []uint8) (uint32, uint32) {
+
+	if len(hole) < 2 || len(b.triplets) == 0 || len(b.triplets) > 10 {
+		return 0, InvalidHandIndex
+	}
+
+	best := uint32(0)
+
+	for h1 := 0; h1 < len(hole); h1++ {
+		for h2 := h1 + 1; h2 < len(hole); h2++ {
+			for _, triplet := range b.triplets {
+				if value, _ := triplet.Eval(hole[h1], hole[h2]); value > best {
+					best = value
+				}
+			}
+		}
+	}
+
+	return best, best >> handTypeShift
+}
+
+// Eval returns the value and hand type index of the best hand made from two
+// of the hole cards and three of the three to five board cards.
+func (o OmahaEvaluator) Eval(hole []uint8, board []uint8) (uint32, uint32) {
+	return o.Board(board).Eval(hole)
+}
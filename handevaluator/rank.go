@@ -0,0 +1,245 @@
+package handevaluator
+
+import (
+	"math/bits"
+	"sort"
+)
+
+// Hand type indexes as returned alongside hand values, matching HandTypes().
+const (
+	HighCard = iota + 1
+	OnePair
+	TwoPairs
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+const handTypeShift = 12
+
+// wheelMask is the rank mask of A-2-3-4-5, the lowest straight.
+const wheelMask uint16 = 1<<12 | 0xf
+
+// strengthKey packs a hand type and up to five ranks (0 = deuce ... 12 = ace)
+// in order of significance, so that comparing keys compares hands.
+type strengthKey uint32
+
+func newStrengthKey(handType int, ranks ...int) strengthKey {
+	key := strengthKey(handType) << 20
+	for i, r := range ranks {
+		key |= strengthKey(r) << (16 - 4*uint(i))
+	}
+	return key
+}
+
+func (k strengthKey) handType() uint32 {
+	return uint32(k >> 20)
+}
+
+// classValues maps every one of the 7462 distinct five card hands to its
+// value in the Two Plus Two format: hand type << 12 | rank within type.
+var classValues = buildClassValues()
+
+// forEachClass calls f once for every distinct five card hand, described by
+// its rank counts and whether it is suited.
+func forEachClass(f func(counts *[13]uint8, mask uint16, suited bool)) {
+
+	counts := [13]uint8{}
+
+	var enumerate func(fromRank int, remaining int)
+	enumerate = func(fromRank int, remaining int) {
+		if remaining == 0 {
+			mask := rankMaskOf(&counts)
+			f(&counts, mask, false)
+			if bits.OnesCount16(mask) == 5 {
+				f(&counts, mask, true)
+			}
+			return
+		}
+		for r := fromRank; r < 13; r++ {
+			if counts[r] == 4 {
+				continue
+			}
+			counts[r]++
+			enumerate(r, remaining-1)
+			counts[r]--
+		}
+	}
+	enumerate(0, 5)
+}
+
+func buildClassValues() map[strengthKey]uint32 {
+
+	keys := []strengthKey{}
+	forEachClass(func(counts *[13]uint8, mask uint16, suited bool) {
+		flushMask := uint16(0)
+		if suited {
+			flushMask = mask
+		}
+		keys = append(keys, bestStrength(counts, mask, flushMask))
+	})
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	values := make(map[strengthKey]uint32, len(keys))
+	indexInType := uint32(0)
+	lastType := uint32(0)
+	for _, k := range keys {
+		if k.handType() != lastType {
+			lastType = k.handType()
+			indexInType = 0
+		}
+		indexInType++
+		values[k] = lastType<<handTypeShift | indexInType
+	}
+
+	return values
+}
+
+func rankMaskOf(counts *[13]uint8) uint16 {
+	var mask uint16
+	for r, n := range counts {
+		if n > 0 {
+			mask |= 1 << uint(r)
+		}
+	}
+	return mask
+}
+
+// straightHigh returns the rank of the highest card of the best straight in
+// mask, or -1 when there is none. The wheel is reported as a five high.
+func straightHigh(mask uint16) int {
+	for high := 12; high >= 4; high-- {
+		run := uint16(0x1f) << uint(high-4)
+		if mask&run == run {
+			return high
+		}
+	}
+	if mask&wheelMask == wheelMask {
+		return 3
+	}
+	return -1
+}
+
+// topRanks returns the n highest ranks present in mask, best first.
+func topRanks(mask uint16, n int) []int {
+	ranks := make([]int, 0, n)
+	for r := 12; r >= 0 && len(ranks) < n; r-- {
+		if mask&(1<<uint(r)) != 0 {
+			ranks = append(ranks, r)
+		}
+	}
+	return ranks
+}
+
+func highestWithCount(counts *[13]uint8, atLeast uint8, except ...int) int {
+Ranks:
+	for r := 12; r >= 0; r-- {
+		if counts[r] < atLeast {
+			continue
+		}
+		for _, e := range except {
+			if e == r {
+				continue Ranks
+			}
+		}
+		return r
+	}
+	return -1
+}
+
+func without(mask uint16, ranks ...int) uint16 {
+	for _, r := range ranks {
+		mask &^= 1 << uint(r)
+	}
+	return mask
+}
+
+// bestStrength returns the strength of the best five card hand that can be
+// made from five to seven cards described by their rank counts. flushMask
+// holds the ranks of the suit with five or more cards, if there is one.
+func bestStrength(counts *[13]uint8, mask uint16, flushMask uint16) strengthKey {
+
+	if flushMask != 0 {
+		if high := straightHigh(flushMask); high >= 0 {
+			return newStrengthKey(StraightFlush, high)
+		}
+	}
+
+	if key, ok := fullHouseOrBetter(counts, mask); ok {
+		return key
+	}
+
+	if flushMask != 0 {
+		return newStrengthKey(Flush, topRanks(flushMask, 5)...)
+	}
+
+	if high := straightHigh(mask); high >= 0 {
+		return newStrengthKey(Straight, high)
+	}
+
+	return pairedStrength(counts, mask)
+}
+
+func fullHouseOrBetter(counts *[13]uint8, mask uint16) (strengthKey, bool) {
+
+	if quads := highestWithCount(counts, 4); quads >= 0 {
+		return newStrengthKey(FourOfAKind, append([]int{quads}, topRanks(without(mask, quads), 1)...)...), true
+	}
+
+	if trips := highestWithCount(counts, 3); trips >= 0 {
+		if pair := highestWithCount(counts, 2, trips); pair >= 0 {
+			return newStrengthKey(FullHouse, trips, pair), true
+		}
+	}
+
+	return 0, false
+}
+
+// pairedStrength ranks a hand by its pairs alone, as if straights and
+// flushes didn't exist.
+func pairedStrength(counts *[13]uint8, mask uint16) strengthKey {
+
+	if key, ok := fullHouseOrBetter(counts, mask); ok {
+		return key
+	}
+
+	if trips := highestWithCount(counts, 3); trips >= 0 {
+		return newStrengthKey(ThreeOfAKind, append([]int{trips}, topRanks(without(mask, trips), 2)...)...)
+	}
+
+	if high := highestWithCount(counts, 2); high >= 0 {
+		if low := highestWithCount(counts, 2, high); low >= 0 {
+			return newStrengthKey(TwoPairs, append([]int{high, low}, topRanks(without(mask, high, low), 1)...)...)
+		}
+		return newStrengthKey(OnePair, append([]int{high}, topRanks(without(mask, high), 3)...)...)
+	}
+
+	return newStrengthKey(HighCard, topRanks(mask, 5)...)
+}
+
+// rankCards evaluates five to seven distinct cards (1-52) from first
+// principles and returns the value the lookup table holds for them.
+func rankCards(cards []uint8) uint32 {
+
+	counts := [13]uint8{}
+	suitMasks := [4]uint16{}
+
+	for _, c := range cards {
+		rank, suit := (c-1)>>2, (c-1)&3
+		counts[rank]++
+		suitMasks[suit] |= 1 << rank
+	}
+
+	flushMask := uint16(0)
+	for _, m := range suitMasks {
+		if bits.OnesCount16(m) >= 5 {
+			flushMask = m
+		}
+	}
+
+	return classValues[bestStrength(&counts, rankMaskOf(&counts), flushMask)]
+}
@@ -0,0 +1,151 @@
+package handevaluator
+
+import "math/bits"
+
+// shortDeckWheel is A-6-7-8-9, the lowest straight without the deuces to
+// fives. It ranks as a nine high straight.
+const shortDeckWheel uint16 = 1<<12 | 0xf<<4
+
+// ShortDeckEvaluator ranks hands of the 36 card short deck, six through ace.
+// A flush beats a full house, A-6-7-8-9 is a straight, and depending on the
+// rules three of a kind beats a straight or the other way round.
+//
+// Values compare like those of every other evaluator but are short deck
+// values, not Two Plus Two ones. The hand type index still names the hand
+// in HandTypes().
+type ShortDeckEvaluator struct {
+	order [10]uint32
+}
+
+// NewShortDeck returns a short deck evaluator. With tripsBeatStraight, as in
+// the Triton rules, three of a kind outranks a straight.
+func NewShortDeck(tripsBeatStraight bool) ShortDeckEvaluator {
+
+	e := ShortDeckEvaluator{}
+	for i, handType := range []uint32{HighCard, OnePair, TwoPairs, ThreeOfAKind, Straight, FullHouse, Flush, FourOfAKind, StraightFlush} {
+		e.order[handType] = uint32(i) + 1
+	}
+
+	if tripsBeatStraight {
+		e.order[ThreeOfAKind], e.order[Straight] = e.order[Straight], e.order[ThreeOfAKind]
+	}
+
+	return e
+}
+
+func shortDeckStraightHigh(mask uint16) int {
+	for high := 12; high >= 8; high-- {
+		run := uint16(0x1f) << uint(high-4)
+		if mask&run == run {
+			return high
+		}
+	}
+	if mask&shortDeckWheel == shortDeckWheel {
+		return 7
+	}
+	return -1
+}
+
+// packRanks appends the n highest ranks of mask to ranks packed four bits
+// each, the way strength keys hold them.
+func packRanks(ranks uint32, mask uint16, n int) uint32 {
+	for i := 0; i < n && mask != 0; i++ {
+		r := 15 - bits.LeadingZeros16(mask)
+		ranks = ranks<<4 | uint32(r)
+		mask &^= 1 << uint(r)
+	}
+	return ranks
+}
+
+// Eval returns the value and hand type index of the best short deck hand
+// made from five, six or seven cards.
+func (e ShortDeckEvaluator) Eval(cards ...uint8) (uint32, uint32) {
+
+	if len(cards) < 5 || len(cards) > 7 {
+		return 0, InvalidHandIndex
+	}
+
+	counts := [13]uint8{}
+	suitMasks := [4]uint16{}
+	var mask, pairs, trips, quads uint16
+
+	for _, c := range cards {
+		if c < 1 || c > 52 {
+			return 0, InvalidHandIndex
+		}
+		rank, suit := (c-1)>>2, (c-1)&3
+		counts[rank]++
+		suitMasks[suit] |= 1 << rank
+		mask |= 1 << rank
+		switch counts[rank] {
+		case 2:
+			pairs |= 1 << rank
+		case 3:
+			trips |= 1 << rank
+		case 4:
+			quads |= 1 << rank
+		}
+	}
+
+	flushMask := uint16(0)
+	for _, m := range suitMasks {
+		if bits.OnesCount16(m) >= 5 {
+			flushMask = m
+		}
+	}
+
+	best := func(handType uint32, ranks uint32, width int) (uint32, uint32) {
+		return e.order[handType]<<20 | ranks<<(4*uint(5-width)), handType
+	}
+
+	if high := shortDeckStraightHigh(flushMask); flushMask != 0 && high >= 0 {
+		return best(StraightFlush, uint32(high), 1)
+	}
+
+	if quads != 0 {
+		q := packRanks(0, quads, 1)
+		return best(FourOfAKind, packRanks(q, mask&^(1<<q), 1), 2)
+	}
+
+	// Seven cards can't hold both a flush and a full house, so the order of
+	// these two checks only matters between players.
+	if flushMask != 0 {
+		return best(Flush, packRanks(0, flushMask, 5), 5)
+	}
+
+	if trips != 0 {
+		t := packRanks(0, trips, 1)
+		if others := pairs &^ (1 << t); others != 0 {
+			return best(FullHouse, packRanks(t, others, 1), 2)
+		}
+	}
+
+	straightHigh := shortDeckStraightHigh(mask)
+	tripsFirst := e.order[ThreeOfAKind] > e.order[Straight]
+
+	if straightHigh >= 0 && (trips == 0 || !tripsFirst) {
+		return best(Straight, uint32(straightHigh), 1)
+	}
+
+	if trips != 0 {
+		t := packRanks(0, trips, 1)
+		return best(ThreeOfAKind, packRanks(t, mask&^(1<<t), 2), 3)
+	}
+
+	if bits.OnesCount16(pairs) >= 2 {
+		twoPairs := packRanks(0, pairs, 2)
+		high, low := twoPairs>>4, twoPairs&0xf
+		return best(TwoPairs, packRanks(twoPairs, mask&^(1<<high|1<<low), 1), 3)
+	}
+
+	if pairs != 0 {
+		p := packRanks(0, pairs, 1)
+		return best(OnePair, packRanks(p, mask&^(1<<p), 3), 4)
+	}
+
+	return best(HighCard, packRanks(0, mask, 5), 5)
+}
+
+func (e ShortDeckEvaluator) PartialEvaluation(partial ...[]uint8) PartialEvaluation {
+	return newCardsPartialEvaluation(e, partial...)
+}
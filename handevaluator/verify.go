@@ -0,0 +1,169 @@
+package handevaluator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math/rand"
+)
+
+// A table may start with a header of headerEntries little endian entries:
+// the magic "HRTB", the format version, the number of entries that follow
+// and the CRC-32 (IEEE) of those entries. Tables without a header are the
+// plain HandRanks.dat layout and have to match StandardChecksum.
+const (
+	headerMagic   uint32 = 'H' | 'R'<<8 | 'T'<<16 | 'B'<<24
+	headerVersion uint32 = 1
+	headerEntries        = 4
+)
+
+// StandardChecksum is the CRC-32 (IEEE) of the standard HandRanks.dat.
+const StandardChecksum uint32 = 0x7808da57
+
+const spotCheckCount = 1000
+
+type tableHeader struct {
+	version  uint32
+	entries  uint32
+	checksum uint32
+}
+
+// splitHeader separates the optional header from the entries it describes.
+func splitHeader(ranks []uint32) (*tableHeader, []uint32) {
+	if len(ranks) < headerEntries || ranks[0] != headerMagic {
+		return nil, ranks
+	}
+
+	return &tableHeader{
+		version:  ranks[1],
+		entries:  ranks[2],
+		checksum: ranks[3],
+	}, ranks[headerEntries:]
+}
+
+// checkTable rejects tables that are truncated, corrupted or of an unknown
+// version before they can be used to evaluate hands.
+func checkTable(ranks []uint32) ([]uint32, error) {
+
+	header, ranks := splitHeader(ranks)
+	expectedChecksum := StandardChecksum
+
+	if header != nil {
+		if header.version != headerVersion {
+			return nil, fmt.Errorf("hand ranks table version %d is not supported, expected %d", header.version, headerVersion)
+		}
+		if int(header.entries) != len(ranks) {
+			return nil, fmt.Errorf("hand ranks table header promises %d entries but %d were found", header.entries, len(ranks))
+		}
+		expectedChecksum = header.checksum
+	}
+
+	if len(ranks) != TableSize {
+		return nil, fmt.Errorf("hand ranks table has %d entries, expected %d", len(ranks), TableSize)
+	}
+
+	if checksum := tableChecksum(ranks); checksum != expectedChecksum {
+		return nil, fmt.Errorf("hand ranks table checksum is %#08x, expected %#08x", checksum, expectedChecksum)
+	}
+
+	if err := spotCheck(ranks); err != nil {
+		return nil, err
+	}
+
+	return ranks, nil
+}
+
+func tableChecksum(ranks []uint32) uint32 {
+
+	checksum := uint32(0)
+	buffer := make([]byte, readChunkSize)
+
+	for len(ranks) > 0 {
+		n := len(buffer) / 4
+		if n > len(ranks) {
+			n = len(ranks)
+		}
+		for i, r := range ranks[:n] {
+			binary.LittleEndian.PutUint32(buffer[4*i:], r)
+		}
+		checksum = crc32.Update(checksum, crc32.IEEETable, buffer[:4*n])
+		ranks = ranks[n:]
+	}
+
+	return checksum
+}
+
+// spotCheck compares the table against hands evaluated from first principles:
+// the best and worst hands and a fixed pseudo random set of five, six and
+// seven card hands.
+func spotCheck(ranks []uint32) error {
+
+	hands := [][]uint8{
+		{52, 48, 44, 40, 36, 1, 2},
+		{21, 13, 9, 5, 2},
+		{21, 14, 9, 6, 2, 1},
+	}
+
+	rGen := rand.New(rand.NewSource(7462))
+	for i := 0; i < spotCheckCount; i++ {
+		deck := rGen.Perm(52)
+		hand := make([]uint8, 5+i%3)
+		for j := range hand {
+			hand[j] = uint8(deck[j] + 1)
+		}
+		hands = append(hands, hand)
+	}
+
+	for _, hand := range hands {
+		value, err := walk(ranks, hand)
+		if err != nil {
+			return err
+		}
+		if expected := rankCards(hand); value != expected {
+			return fmt.Errorf("hand ranks table values %v at %d, expected %d", hand, value, expected)
+		}
+	}
+
+	return nil
+}
+
+// walk looks up the value of five to seven cards without trusting the table
+// to stay within bounds.
+func walk(ranks []uint32, cards []uint8) (uint32, error) {
+
+	p := uint32(statesPerEntry)
+	for i := 0; i <= len(cards); i++ {
+		offset := p
+		if i < len(cards) {
+			offset += uint32(cards[i])
+		} else if len(cards) == 7 {
+			break
+		}
+
+		if int(offset) >= len(ranks) {
+			return 0, fmt.Errorf("hand ranks table points outside itself looking up %v", cards)
+		}
+		p = ranks[offset]
+	}
+
+	return p, nil
+}
+
+// WriteVersionedTable writes ranks like WriteTable, preceded by a header with
+// the format version, entry count and checksum.
+func WriteVersionedTable(w io.Writer, ranks []uint32) error {
+
+	buffered := bufio.NewWriter(w)
+
+	if err := WriteTable(buffered, []uint32{headerMagic, headerVersion, uint32(len(ranks)), tableChecksum(ranks)}); err != nil {
+		return err
+	}
+
+	if err := WriteTable(buffered, ranks); err != nil {
+		return err
+	}
+
+	return buffered.Flush()
+}
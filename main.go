@@ -2,16 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"holdem/combinations"
 	"holdem/deck"
 	"holdem/handevaluator"
 	"holdem/odds"
+	"holdem/ranges"
 	"log"
 	"net/http"
 	_ "net/http/pprof"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type patternHandler struct {
@@ -40,16 +43,21 @@ func caselessMatcher(handlers []patternHandler) func(w http.ResponseWriter, r *h
 	}
 }
 
-func handleRequests() {
-	evaluator, err := handevaluator.New()
-
-	if err != nil {
-		fmt.Println(err)
-		return
+func loadEvaluator(tablePath string, mapped bool) (handevaluator.HandEvaluator, error) {
+	switch {
+	case mapped:
+		return handevaluator.NewMapped(tablePath)
+	case tablePath == handevaluator.DefaultTablePath:
+		return handevaluator.New()
+	default:
+		return handevaluator.NewFromFile(tablePath)
 	}
+}
 
+func handleRequests(evaluator handevaluator.Evaluator, shortDeckTripsBeatStraight bool) {
 	deck := deck.New()
 	oddsCalculator := odds.NewCalculator(evaluator, combinations.New(), deck)
+	oddsCalculator.SetShortDeckRules(shortDeckTripsBeatStraight)
 
 	http.HandleFunc("/", caselessMatcher([]patternHandler{
 		{pattern: "/evaluatehand", handler: getHandEvaluator(evaluator, deck)},
@@ -109,31 +117,46 @@ func handleRequests() {
 // 		json.NewEncoder(w).Encode(combinations)
 // 	}
 // }
-func getHandEvaluator(evaluator handevaluator.HandEvaluator, deck deck.Deck) func(w http.ResponseWriter, r *http.Request) {
+func getHandEvaluator(evaluator handevaluator.Evaluator, fullDeck deck.Deck) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 
 		fmt.Println("Endpoint Hit: evaluate hand")
 
-		cards := r.URL.Query()["c"]
+		query := r.URL.Query()
+		cards := append(append(query["c"], query["hero"]...), query["board"]...)
 
-		if len(cards) != 7 {
-			badRequest(w, "Please provide 7 cards")
+		hand, err := fullDeck.Parse(cards)
+
+		if err != nil {
+			badRequest(w, err.Error())
 			return
 		}
 
-		hand, err := deck.CardStringsToNumbers(cards)
+		if len(hand) < 5 || len(hand) > 7 {
+			badRequest(w, "Please provide 5, 6 or 7 cards")
+			return
+		}
+
+		if duplicate, found := hasDuplicate(hand); found {
+			badRequest(w, "found more than one "+duplicate.String())
+			return
+		}
+
+		value, handTypeIndex := evaluator.Eval(deck.Numbers(hand)...)
+
+		description, err := handevaluator.Describe(hand)
 
 		if err != nil {
 			badRequest(w, err.Error())
 			return
 		}
 
-		partialEvaluation := evaluator.PartialEvaluation(hand[:5])
-		value, handTypeIndex := partialEvaluation.Eval(hand[5], hand[6])
-
 		json.NewEncoder(w).Encode(handevaluator.EvaluatedHand{
-			Value:    value,
-			HandName: handevaluator.HandTypes()[handTypeIndex],
+			Value:            value,
+			HandName:         handevaluator.HandTypes()[handTypeIndex],
+			EquivalenceClass: handevaluator.EquivalenceClass(value),
+			BestCards:        description.BestCards,
+			Description:      description.Text,
 		})
 	}
 }
@@ -143,15 +166,56 @@ func getOddsEvaluator(oddsCalculator odds.OddsCalculator) func(w http.ResponseWr
 
 		fmt.Println("Endpoint Hit: evaluate odds")
 
-		community := r.URL.Query()["community"]
-		hero := r.URL.Query()["hero"]
+		query := r.URL.Query()
+		community, err := deck.ParseCards(append(query["community"], query["board"]...))
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		hero, err := deck.ParseCards(query["hero"])
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		dead, err := deck.ParseCards(query["dead"])
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		options := odds.Options{}
+		options.MaxShowDowns, err = iQueryParam(r, "maxshowdowns", 0)
 
-		// sampleSize, err := iQueryParam(r, "size", 100000)
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
 
-		// if err != nil {
-		// 	badRequest(w, err.Error())
-		// 	return
-		// }
+		options.TimeBudget, err = durationQueryParam(r, "budget")
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		options.TargetPrecision, err = fQueryParam(r, "precision", 0)
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		calculator, err := oddsCalculator.WithOptions(options)
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
 
 		villainCount, err := iQueryParam(r, "villaincount", 1)
 
@@ -160,7 +224,69 @@ func getOddsEvaluator(oddsCalculator odds.OddsCalculator) func(w http.ResponseWr
 			return
 		}
 
-		result, err := oddsCalculator.Calculate(hero, community, villainCount)
+		game, err := gameQueryParam(r)
+
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		var result odds.Odds
+
+		if len(hero) == 0 && len(query["villain"]) == 0 && len(query["range"]) > 0 {
+			// Without a hero, the ranges play against each other.
+			playerRanges := []ranges.Range{}
+
+			for _, v := range query["range"] {
+				var playerRange ranges.Range
+				playerRange, err = ranges.Parse(v)
+
+				if err != nil {
+					badRequest(w, err.Error())
+					return
+				}
+
+				playerRanges = append(playerRanges, playerRange)
+			}
+
+			result, err = calculator.CalculateRanges(game, community, playerRanges, dead...)
+		} else if len(query["villain"]) > 0 || len(query["range"]) > 0 {
+			request := odds.Request{Game: game, Hero: hero, Community: community, Dead: dead}
+
+			for _, v := range query["villain"] {
+				var hand []deck.Card
+				hand, err = deck.ParseHand(v)
+
+				if err != nil {
+					badRequest(w, err.Error())
+					return
+				}
+
+				request.Villains = append(request.Villains, odds.Villain{Hand: hand})
+			}
+
+			// Every range value is the range of one more villain.
+			for _, v := range query["range"] {
+				var villainRange ranges.Range
+				villainRange, err = ranges.Parse(v)
+
+				if err != nil {
+					badRequest(w, err.Error())
+					return
+				}
+
+				request.Villains = append(request.Villains, odds.Villain{Range: &villainRange})
+			}
+
+			// villaincount adds villains with random hands.
+			for i := len(request.Villains); i < villainCount; i++ {
+				request.Villains = append(request.Villains, odds.Villain{})
+			}
+
+			result, err = calculator.CalculateVersus(request)
+		} else {
+			result, err = calculator.Calculate(game, hero, community, villainCount, dead...)
+		}
 
 		if err != nil {
 			badRequest(w, err.Error())
@@ -171,6 +297,30 @@ func getOddsEvaluator(oddsCalculator odds.OddsCalculator) func(w http.ResponseWr
 	}
 }
 
+func hasDuplicate(cards []deck.Card) (deck.Card, bool) {
+	for i, c := range cards {
+		for _, other := range cards[i+1:] {
+			if other == c {
+				return c, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func gameQueryParam(r *http.Request) (odds.Game, error) {
+
+	values := r.URL.Query()["game"]
+	if len(values) == 0 {
+		return odds.Holdem, nil
+	}
+	if len(values) > 1 {
+		return odds.Holdem, fmt.Errorf("send only one game per call")
+	}
+
+	return odds.ParseGame(values[0])
+}
+
 func iQueryParam(r *http.Request, key string, defaultValue int) (int, error) {
 
 	values := r.URL.Query()[key]
@@ -184,6 +334,55 @@ func iQueryParam(r *http.Request, key string, defaultValue int) (int, error) {
 	return strconv.Atoi(values[0])
 }
 
+func fQueryParam(r *http.Request, key string, defaultValue float64) (float64, error) {
+
+	values := r.URL.Query()[key]
+	if len(values) == 0 {
+		return defaultValue, nil
+	}
+	if len(values) > 1 {
+		return 0, fmt.Errorf("send only one " + key + " per call")
+	}
+
+	return strconv.ParseFloat(values[0], 64)
+}
+
+// durationQueryParam reads a duration such as "500ms" or "2s", zero when it
+// isn't sent.
+func durationQueryParam(r *http.Request, key string) (time.Duration, error) {
+
+	values := r.URL.Query()[key]
+	if len(values) == 0 {
+		return 0, nil
+	}
+	if len(values) > 1 {
+		return 0, fmt.Errorf("send only one " + key + " per call")
+	}
+
+	return time.ParseDuration(values[0])
+}
+
 func main() {
-	handleRequests()
+	tablePath := flag.String("table", handevaluator.DefaultTablePath, "path of the hand ranks lookup table")
+	mapped := flag.Bool("mmap", false, "memory map the lookup table read-only instead of loading a private copy")
+	evaluatorName := flag.String("evaluator", "table", "hand evaluator to use: table (Two Plus Two lookup table) or cactuskev (no table, less memory, slower)")
+	tripsBeatStraight := flag.Bool("shortdeck-trips-beat-straight", true, "in short deck games three of a kind beats a straight")
+	flag.Parse()
+
+	switch *evaluatorName {
+	case "cactuskev":
+		handleRequests(handevaluator.NewCactusKev(), *tripsBeatStraight)
+	case "table":
+		evaluator, err := loadEvaluator(*tablePath, *mapped)
+
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		// handleRequests only returns by exiting, which releases the table.
+		handleRequests(&evaluator, *tripsBeatStraight)
+	default:
+		fmt.Println("unknown evaluator " + *evaluatorName)
+	}
 }
@@ -0,0 +1,140 @@
+package odds
+
+import (
+	"holdem/list"
+	"math/rand"
+)
+
+// drawCount is how many times every player draws in triple draw.
+const drawCount = 3
+
+// patRank is the highest rank a 2-7 player stands pat with, the nine.
+const patRank = 7
+
+// keepRank is the highest rank a 2-7 player keeps when drawing, the seven.
+const keepRank = 5
+
+// tripleDraw deals the draws of one deal of triple draw. Players draw in
+// turn, the hero first, from the cards nobody was dealt. When those run out
+// the discards so far are shuffled to draw from, as at the table.
+type tripleDraw struct {
+	rGen *rand.Rand
+	// stub holds the cards left to draw.
+	stub []uint8
+	// pile holds the discards not yet shuffled back.
+	pile      []uint8
+	kept      []uint8
+	discarded []uint8
+}
+
+func newTripleDraw(rGen *rand.Rand) *tripleDraw {
+	return &tripleDraw{
+		rGen:      rGen,
+		kept:      make([]uint8, 0, 5),
+		discarded: make([]uint8, 0, 5),
+	}
+}
+
+// play makes every hand draw three times by deuceToSevenKeeps. The stub is
+// drawn from by swapping cards within it, so that it keeps its cards.
+func (d *tripleDraw) play(hands [][]uint8, stub []uint8) {
+
+	d.stub = stub
+	d.pile = d.pile[:0]
+
+	for round := 0; round < drawCount; round++ {
+		for _, hand := range hands {
+			d.kept = deuceToSevenKeeps(hand, d.kept[:0])
+			if len(d.kept) == len(hand) {
+				continue
+			}
+
+			d.discarded = d.discarded[:0]
+			for _, c := range hand {
+				if !list.Includes(d.kept, c) {
+					d.discarded = append(d.discarded, c)
+				}
+			}
+
+			copy(hand, d.kept)
+			discardedOwn := false
+			for i := len(d.kept); i < len(hand); i++ {
+				if len(d.stub) == 0 {
+					if len(d.pile) == 0 {
+						// Only this player's discards are left.
+						d.pile = append(d.pile, d.discarded...)
+						discardedOwn = true
+					}
+					d.stub, d.pile = d.pile, make([]uint8, 0, len(d.pile))
+				}
+				hand[i] = d.drawCard()
+			}
+
+			if !discardedOwn {
+				d.pile = append(d.pile, d.discarded...)
+			}
+		}
+	}
+}
+
+// drawCard takes a random card of the stub, moving it to the stub's end.
+func (d *tripleDraw) drawCard() uint8 {
+	last := len(d.stub) - 1
+	j := d.rGen.Intn(len(d.stub))
+	d.stub[j], d.stub[last] = d.stub[last], d.stub[j]
+	c := d.stub[last]
+	d.stub = d.stub[:last]
+	return c
+}
+
+// deuceToSevenKeeps appends the cards a 2-7 player keeps to kept, by a
+// simple policy: stand pat on any five different ranks nine high or lower
+// that are neither a straight nor a flush, otherwise keep one card of every
+// rank seven or lower, breaking five such cards by their highest.
+func deuceToSevenKeeps(hand []uint8, kept []uint8) []uint8 {
+
+	seen := [13]bool{}
+	distinct, lowest, highest := 0, 12, 0
+	suits := uint8(0)
+	for _, c := range hand {
+		rank := int(c-1) >> 2
+		if !seen[rank] {
+			seen[rank] = true
+			distinct++
+		}
+		if rank < lowest {
+			lowest = rank
+		}
+		if rank > highest {
+			highest = rank
+		}
+		suits |= 1 << ((c - 1) & 3)
+	}
+
+	straight := distinct == 5 && highest-lowest == 4
+	flush := suits&(suits-1) == 0
+	if distinct == 5 && highest <= patRank && !straight && !flush {
+		return append(kept, hand...)
+	}
+
+	seen = [13]bool{}
+	for _, c := range hand {
+		rank := int(c-1) >> 2
+		if rank <= keepRank && !seen[rank] {
+			seen[rank] = true
+			kept = append(kept, c)
+		}
+	}
+
+	if len(kept) == 5 {
+		top := 0
+		for i, c := range kept {
+			if c > kept[top] {
+				top = i
+			}
+		}
+		kept = append(kept[:top], kept[top+1:]...)
+	}
+
+	return kept
+}
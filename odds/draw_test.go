@@ -0,0 +1,70 @@
+package odds
+
+import (
+	"holdem/deck"
+	"math/rand"
+	"testing"
+)
+
+func TestDeuceToSevenKeeps(t *testing.T) {
+
+	keeps := []struct {
+		name string
+		hand string
+		kept int
+	}{
+		{"seven low", "7c5d4h3s2c", 5},
+		{"nine low", "9c8d4h3s2c", 5},
+		{"ten low", "Tc5d4h3s2c", 4},
+		{"straight", "6c5d4h3s2c", 4},
+		{"flush", "7c5c4c3c2c", 4},
+		{"pair", "7c7d4h3s2c", 4},
+		{"one low card", "KcQdJhTs2c", 1},
+		{"nothing", "AcKdQhJs9c", 0},
+	}
+
+	for _, k := range keeps {
+		hand := deck.Numbers(cards(t, k.hand))
+		kept := deuceToSevenKeeps(hand, nil)
+		if len(kept) != k.kept {
+			t.Errorf("%s: kept %d cards, expected %d", k.name, len(kept), k.kept)
+		}
+	}
+}
+
+// However short the deck runs, every draw leaves each card in exactly one
+// hand or in the stub and the pile.
+func TestTripleDrawKeepsTheDeck(t *testing.T) {
+
+	rGen := rand.New(rand.NewSource(1))
+	draw := newTripleDraw(rGen)
+
+	for deal := 0; deal < 1000; deal++ {
+		deck := rGen.Perm(52)
+		hands := make([][]uint8, 10)
+		for h := range hands {
+			hands[h] = make([]uint8, 5)
+			for i := range hands[h] {
+				hands[h][i] = uint8(deck[h*5+i] + 1)
+			}
+		}
+		stub := []uint8{uint8(deck[50] + 1), uint8(deck[51] + 1)}
+
+		draw.play(hands, stub)
+
+		seen := [53]bool{}
+		count := 0
+		for _, cs := range append(append(hands, draw.stub), draw.pile) {
+			for _, c := range cs {
+				if seen[c] {
+					t.Fatalf("deal %d: %d is dealt twice", deal, c)
+				}
+				seen[c] = true
+				count++
+			}
+		}
+		if count != 52 {
+			t.Fatalf("deal %d: %d cards left, expected 52", deal, count)
+		}
+	}
+}
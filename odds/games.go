@@ -0,0 +1,137 @@
+package odds
+
+import (
+	"fmt"
+	"holdem/handevaluator"
+	"strings"
+)
+
+// Game is a poker variant the calculator can deal.
+type Game int
+
+const (
+	Holdem Game = iota
+	Omaha
+	// OmahaHiLo splits the pot between the best high hand and the best eight
+	// or better low.
+	OmahaHiLo
+	// StudHiLo is seven card stud split between the best high hand and the
+	// best eight or better low.
+	StudHiLo
+	// ShortDeck is hold'em dealt from the 36 card deck of sixes to aces.
+	ShortDeck
+	// Razz is seven card stud won by the best A-5 low.
+	Razz
+	// DeuceToSevenTripleDraw is five card draw won by the best 2-7 low, with
+	// three draws for every player.
+	DeuceToSevenTripleDraw
+)
+
+var gameNames = map[Game]string{
+	Holdem:                 "holdem",
+	Omaha:                  "omaha",
+	OmahaHiLo:              "omahahilo",
+	StudHiLo:               "studhilo",
+	ShortDeck:              "shortdeck",
+	Razz:                   "razz",
+	DeuceToSevenTripleDraw: "27tripledraw",
+}
+
+func ParseGame(name string) (Game, error) {
+	for game, gameName := range gameNames {
+		if strings.EqualFold(name, gameName) {
+			return game, nil
+		}
+	}
+	return Holdem, fmt.Errorf("%s is not a supported game", name)
+}
+
+func (g Game) String() string {
+	return gameNames[g]
+}
+
+func (g Game) holeCardsCount() int {
+	switch g {
+	case Omaha, OmahaHiLo:
+		return 4
+	case StudHiLo, Razz:
+		return 7
+	case DeuceToSevenTripleDraw:
+		return 5
+	default:
+		return 2
+	}
+}
+
+func (g Game) isOmaha() bool {
+	return g == Omaha || g == OmahaHiLo
+}
+
+// isStud tells games where every player has their own seven cards and
+// there are no community cards.
+func (g Game) isStud() bool {
+	return g == StudHiLo || g == Razz
+}
+
+// isDraw tells games where every player has five cards of their own and
+// there are no community cards.
+func (g Game) isDraw() bool {
+	return g == DeuceToSevenTripleDraw
+}
+
+// evaluator returns the evaluator deciding the pot, or the high half of it
+// in split pot games.
+func (g Game) evaluator(high handevaluator.Evaluator) handevaluator.Evaluator {
+	switch g {
+	case Razz:
+		return handevaluator.NewAceToFive()
+	case DeuceToSevenTripleDraw:
+		return handevaluator.NewDeuceToSeven()
+	default:
+		return high
+	}
+}
+
+func (g Game) isHiLo() bool {
+	return g == OmahaHiLo || g == StudHiLo
+}
+
+// totalTestsDesired is how many showdowns a calculation aims for. An Omaha
+// hand is the best of 60 five card hands, so it gets fewer showdowns, and
+// short deck hands are evaluated without the lookup table.
+func (g Game) totalTestsDesired() float64 {
+	switch {
+	case g.isOmaha():
+		return 1e7
+	case g == ShortDeck:
+		return 2e8
+	default:
+		return 2e9
+	}
+}
+
+// boardEvaluation evaluates the hands of every player against one complete
+// board.
+type boardEvaluation interface {
+	Eval(hole []uint8) (uint32, uint32)
+}
+
+func (g Game) boardEvaluation(evaluator handevaluator.Evaluator, board []uint8) boardEvaluation {
+	if g.isOmaha() {
+		return handevaluator.NewOmaha(evaluator).Board(board)
+	}
+	return holdemBoard{evaluator.PartialEvaluation(board)}
+}
+
+// lowBoardEvaluation evaluates the eight or better lows of split pot games.
+func (g Game) lowBoardEvaluation(board []uint8) boardEvaluation {
+	return handevaluator.NewEightOrBetter().OmahaBoard(board)
+}
+
+type holdemBoard struct {
+	partialEvaluation handevaluator.PartialEvaluation
+}
+
+func (b holdemBoard) Eval(hole []uint8) (uint32, uint32) {
+	return b.partialEvaluation.Eval(hole[0], hole[1])
+}
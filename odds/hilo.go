@@ -0,0 +1,150 @@
+package odds
+
+// handValues are the high and the low value of one player's hand. A low of 0
+// doesn't qualify.
+type handValues struct {
+	high uint32
+	low  uint32
+}
+
+// HiLoTotals counts the showdowns of a split pot game by the part of the pot
+// the hero took. Every showdown is counted once in Scoop, HighOnly, LowOnly,
+// Quartered, Split or Lose.
+type HiLoTotals struct {
+	// Scoop is the whole pot.
+	Scoop int
+	// HighOnly is all of the high half and none of the low half.
+	HighOnly int
+	// LowOnly is all of the low half and none of the high half.
+	LowOnly int
+	// Quartered is a quarter of the pot or less, from a half shared with
+	// other players.
+	Quartered int
+	// Split is any other part of the pot, such as half of it when no low
+	// qualifies or three quarters of it.
+	Split int
+	Lose  int
+	// NoLow counts the showdowns where no hand qualified for low and the high
+	// hand took the whole pot.
+	NoLow int
+}
+
+type HiLoProbabilities struct {
+	Scoop     float32
+	HighOnly  float32
+	LowOnly   float32
+	Quartered float32
+	Split     float32
+	Lose      float32
+	NoLow     float32
+}
+
+type HiLo struct {
+	Probabilities HiLoProbabilities
+	Totals        HiLoTotals
+	// Equity is the hero's average share of the pot as a percentage.
+	Equity float32
+}
+
+func (t *HiLoTotals) add(other HiLoTotals) {
+	t.Scoop += other.Scoop
+	t.HighOnly += other.HighOnly
+	t.LowOnly += other.LowOnly
+	t.Quartered += other.Quartered
+	t.Split += other.Split
+	t.Lose += other.Lose
+	t.NoLow += other.NoLow
+}
+
+func newHiLo(totals HiLoTotals, potShare float64, total int) *HiLo {
+	percentage := func(n int) float32 {
+		return 100 * float32(n) / float32(total)
+	}
+
+	return &HiLo{
+		Totals: totals,
+		Probabilities: HiLoProbabilities{
+			Scoop:     percentage(totals.Scoop),
+			HighOnly:  percentage(totals.HighOnly),
+			LowOnly:   percentage(totals.LowOnly),
+			Quartered: percentage(totals.Quartered),
+			Split:     percentage(totals.Split),
+			Lose:      percentage(totals.Lose),
+			NoLow:     percentage(totals.NoLow),
+		},
+		Equity: float32(100 * potShare / float64(total)),
+	}
+}
+
+// settle records one showdown of a split pot game: the high half goes to the
+// best high hands, the low half to the best qualifying lows, and the high
+// hands take the whole pot when no low qualifies.
+func (r *showDownResults) settle(hero handValues, villains []handValues) {
+
+	bestHigh, bestLow := hero.high, hero.low
+	for _, v := range villains {
+		if v.high > bestHigh {
+			bestHigh = v.high
+		}
+		if v.low > bestLow {
+			bestLow = v.low
+		}
+	}
+
+	highWinners, lowWinners := 1, 1
+	if hero.high != bestHigh {
+		highWinners = 0
+	}
+	if hero.low != bestLow {
+		lowWinners = 0
+	}
+	for _, v := range villains {
+		if v.high == bestHigh {
+			highWinners++
+		}
+		if v.low == bestLow {
+			lowWinners++
+		}
+	}
+
+	highShare, lowShare := 0.0, 0.0
+	if hero.high == bestHigh {
+		highShare = 1 / float64(highWinners)
+	}
+	if bestLow != 0 && hero.low == bestLow {
+		lowShare = 1 / float64(lowWinners)
+	}
+
+	switch {
+	case hero.high < bestHigh:
+		r.lose++
+	case highWinners == 1:
+		r.win++
+	default:
+		r.tie++
+		r.tieVillainCounts[highWinners-1]++
+	}
+
+	share := highShare
+	if bestLow != 0 {
+		share = (highShare + lowShare) / 2
+	} else {
+		r.hiLo.NoLow++
+	}
+	r.potShare += share
+
+	switch {
+	case share == 1:
+		r.hiLo.Scoop++
+	case share == 0:
+		r.hiLo.Lose++
+	case bestLow != 0 && highShare == 1 && lowShare == 0:
+		r.hiLo.HighOnly++
+	case bestLow != 0 && lowShare == 1 && highShare == 0:
+		r.hiLo.LowOnly++
+	case share <= 0.25:
+		r.hiLo.Quartered++
+	default:
+		r.hiLo.Split++
+	}
+}
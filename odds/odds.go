@@ -5,23 +5,28 @@ import (
 	"holdem/combinations"
 	"holdem/deck"
 	"holdem/handevaluator"
-	"holdem/list"
 	"holdem/slicesampler"
 	"math"
+	"math/rand"
 	"runtime"
-	"strings"
 	"sync"
 )
 
 var exists = struct{}{}
 
+// maxMemoEntries bounds how many exact results the memo keeps.
+const maxMemoEntries = 100000
+
 type OddsCalculator struct {
-	deck         deck.Deck
-	evaluator    handevaluator.HandEvaluator
-	combinations combinations.Combinations
-	memo         map[string]memoizedValue
-	memoMutex    *sync.RWMutex
-	preFlopMutex *sync.Mutex
+	deck               deck.Deck
+	evaluator          handevaluator.Evaluator
+	shortDeck          deck.Deck
+	shortDeckEvaluator handevaluator.Evaluator
+	combinations       combinations.Combinations
+	options            Options
+	memo               map[string]memoizedValue
+	memoMutex          *sync.RWMutex
+	preFlopMutex       *sync.Mutex
 	// allPossiblePairs         [][]string
 	// allPossiblePairsIndexMap map[int]map[int]int
 }
@@ -48,23 +53,111 @@ type Totals struct {
 	Lose  int
 	Tie   int
 }
+type PlayerOdds struct {
+	// Hand is empty for players without a known hand.
+	Hand []deck.Card
+	// Range is the range of a player without a known hand, empty when
+	// their hand is random.
+	Range         string `json:",omitempty"`
+	Probabilities Probabilities
+	// Equity is the player's average share of the pot as a percentage.
+	Equity    float32
+	Precision Precision
+}
+
+// ComboOdds are the odds of one combo of a range against the other ranges.
+type ComboOdds struct {
+	Hand []deck.Card
+	// Weight is how often the range plays the combo.
+	Weight float64
+	// Frequency is the percentage of the range's showdowns the combo plays,
+	// once card removal is counted.
+	Frequency     float32
+	Probabilities Probabilities
+	Equity        float32
+	Precision     Precision
+}
+
 type Odds struct {
-	Probabilities    Probabilities
+	Probabilities Probabilities
+	// Equity is the hero's average share of the pot as a percentage: the
+	// showdowns won and, for every tie, the part of the pot split with the
+	// tied villains. In split pot games it counts both halves, as
+	// HiLo.Equity does.
+	Equity           float32
 	Totals           Totals
 	TieVillainCounts map[int]int
 	Hero             map[string]int
+	// HiLo is only set for split pot games.
+	HiLo *HiLo
+	// Exact is set when every runout and every villain hand was played, and
+	// unset when they were sampled.
+	Exact bool
+	// Precision tells how precise the hero's probabilities and equity are.
+	Precision Precision
+	Run       Run
+	// Players holds the hero's odds, then every villain's, when villains
+	// have known hands or ranges, or every range's when ranges play against
+	// each other.
+	Players []PlayerOdds `json:",omitempty"`
+	// Combos holds the odds of every combo of the first range when ranges
+	// play against each other.
+	Combos []ComboOdds `json:",omitempty"`
 	// HandComparisions []HandComparision
+	hiLoTotals      HiLoTotals
+	potShare        float64
+	communityCombos int
+	clusters        clusterMoments
 }
 
-func NewCalculator(evaluator handevaluator.HandEvaluator, combinations combinations.Combinations, deck deck.Deck) OddsCalculator {
+func (o *Odds) add(r showDownResults) {
+	o.Totals.Total += r.total
+	o.Totals.Win += r.win
+	o.Totals.Lose += r.lose
+	o.Totals.Tie += r.tie
+
+	for i, handType := range handevaluator.HandTypes() {
+
+		o.Hero[handType] += r.hero[i]
+	}
+
+	for k, count := range r.tieVillainCounts {
+
+		o.TieVillainCounts[k] += count
+	}
+
+	o.hiLoTotals.add(r.hiLo)
+	o.potShare += r.potShare
+	o.communityCombos += r.communityCombos
+	o.clusters.merge(r.clusters)
+}
+
+func (o *Odds) setProbabilities(game Game) {
+	o.Probabilities.Win = 100 * float32(o.Totals.Win) / float32(o.Totals.Total)
+	o.Probabilities.Lose = 100 * float32(o.Totals.Lose) / float32(o.Totals.Total)
+	o.Probabilities.Tie = 100 * float32(o.Totals.Tie) / float32(o.Totals.Total)
+	o.Equity = float32(100 * o.potShare / float64(o.Totals.Total))
+
+	if game.isHiLo() {
+		o.HiLo = newHiLo(o.hiLoTotals, o.potShare, o.Totals.Total)
+	}
+}
+
+func (o *Odds) setPrecision(standardErrors [statisticsCount]float64) {
+	o.Precision = newPrecision(o.Probabilities, o.Equity, standardErrors)
+}
+
+func NewCalculator(evaluator handevaluator.Evaluator, combinations combinations.Combinations, fullDeck deck.Deck) OddsCalculator {
 
 	c := OddsCalculator{
-		evaluator:    evaluator,
-		combinations: combinations,
-		deck:         deck,
-		memo:         map[string]memoizedValue{},
-		memoMutex:    &sync.RWMutex{},
-		preFlopMutex: &sync.Mutex{},
+		evaluator:          evaluator,
+		combinations:       combinations,
+		deck:               fullDeck,
+		shortDeck:          deck.NewShortDeck(),
+		shortDeckEvaluator: handevaluator.NewShortDeck(true),
+		memo:               map[string]memoizedValue{},
+		memoMutex:          &sync.RWMutex{},
+		preFlopMutex:       &sync.Mutex{},
 		// allPossiblePairs:         allPossibleStringPairs,
 		// allPossiblePairsIndexMap: allPossiblePairsIndexMap,
 	}
@@ -72,6 +165,51 @@ func NewCalculator(evaluator handevaluator.HandEvaluator, combinations combinati
 	return c
 }
 
+// SetShortDeckRules picks whether three of a kind beats a straight in short
+// deck games. It does by default.
+func (calc *OddsCalculator) SetShortDeckRules(tripsBeatStraight bool) {
+	calc.shortDeckEvaluator = handevaluator.NewShortDeck(tripsBeatStraight)
+
+	calc.memoMutex.Lock()
+	defer calc.memoMutex.Unlock()
+	for key := range calc.memo {
+		delete(calc.memo, key)
+	}
+}
+
+func (calc *OddsCalculator) deckFor(game Game) *deck.Deck {
+	if game == ShortDeck {
+		return &calc.shortDeck
+	}
+	return &calc.deck
+}
+
+func (calc *OddsCalculator) evaluatorFor(game Game) handevaluator.Evaluator {
+	if game == ShortDeck {
+		return calc.shortDeckEvaluator
+	}
+	return calc.evaluator
+}
+
+// available returns the cards of the game's deck that aren't dead, in deck
+// order.
+func (calc *OddsCalculator) available(game Game, dead ...[]uint8) ([]uint8, error) {
+
+	shoe := deck.NewShoe(*calc.deckFor(game), 0)
+
+	for _, numbers := range dead {
+		cards, err := deck.FromNumbers(numbers)
+		if err != nil {
+			return nil, err
+		}
+		if err := shoe.Remove(cards...); err != nil {
+			return nil, err
+		}
+	}
+
+	return deck.Numbers(shoe.Remaining()), nil
+}
+
 func (calc *OddsCalculator) hasDuplicates(inputs ...[]uint8) (string, bool) {
 
 	found := map[uint8]struct{}{}
@@ -79,7 +217,7 @@ func (calc *OddsCalculator) hasDuplicates(inputs ...[]uint8) (string, bool) {
 	for _, cards := range inputs {
 		for _, c := range cards {
 			if _, ok := found[c]; ok {
-				return calc.deck.NumberToString(c), true
+				return deck.Card(c).String(), true
 			}
 			found[c] = exists
 		}
@@ -88,39 +226,21 @@ func (calc *OddsCalculator) hasDuplicates(inputs ...[]uint8) (string, bool) {
 	return "", false
 }
 
-func (calc *OddsCalculator) getMemoKey(hero []uint8, community []uint8, villainCount int) string {
-
-	sortedHero := list.SortUInt8s(hero)
-	villains := fmt.Sprintf("<-hero|%dvillains|community->", villainCount)
-
-	if len(community) == 0 && len(hero) == 2 {
-
-		heroValues, err := calc.deck.CardNumbersToStrings(sortedHero)
-		if err != nil {
-			return err.Error()
-		}
-
-		if calc.deck.SameSuit(sortedHero) {
-			return strings.Join(append(heroValues, "same-suit|", villains), "-")
-		}
-		return strings.Join(append(heroValues, "different-suit|", villains), "-")
-	}
-
-	communityStrings, err := calc.deck.CardNumbersToStrings(list.SortUInt8s(community))
+// getMemoKey identifies a calculation up to renaming suits, which doesn't
+// change the odds.
+func (calc *OddsCalculator) getMemoKey(game Game, hero []uint8, community []uint8, villainCount int) string {
 
+	heroCards, err := deck.FromNumbers(hero)
 	if err != nil {
 		return err.Error()
 	}
 
-	heroStrings, err := calc.deck.CardNumbersToStrings(sortedHero)
-
+	communityCards, err := deck.FromNumbers(community)
 	if err != nil {
 		return err.Error()
 	}
 
-	return strings.Join(append(
-		append(heroStrings, villains),
-		communityStrings...), "-")
+	return fmt.Sprintf("%s|%s|%dvillains", game, deck.Canonicalize(heroCards, communityCards).Key(), villainCount)
 }
 
 func (calc *OddsCalculator) readFromMemo(key string) (memoizedValue, bool) {
@@ -130,10 +250,62 @@ func (calc *OddsCalculator) readFromMemo(key string) (memoizedValue, bool) {
 	return value, ok
 }
 
+// writeToMemo keeps an exact result until the memo is full.
+func (calc *OddsCalculator) writeToMemo(key string, result Odds) {
+	calc.memoMutex.Lock()
+	defer calc.memoMutex.Unlock()
+	if len(calc.memo) < maxMemoEntries {
+		calc.memo[key] = memoizedValue{result: result.clone(), sampleSize: result.Totals.Total}
+	}
+}
+
+// clone copies the odds and their maps, so that callers can't change the
+// memoized results.
+func (o Odds) clone() Odds {
+
+	c := o
+	c.TieVillainCounts = map[int]int{}
+	for k, count := range o.TieVillainCounts {
+		c.TieVillainCounts[k] = count
+	}
+	c.Hero = map[string]int{}
+	for handType, count := range o.Hero {
+		c.Hero[handType] = count
+	}
+	if o.HiLo != nil {
+		hiLo := *o.HiLo
+		c.HiLo = &hiLo
+	}
+	return c
+}
+
 func remainingCommunityCardsCount(communityKnown []uint8) int {
 	return 5 - len(communityKnown)
 }
 
+// villainCombosCounts counts the hands each villain can hold once the
+// runout and the villains before them are dealt.
+func villainCombosCounts(game Game, availableCount int, missing int, villainCount int) []int {
+
+	holeCards := game.holeCardsCount()
+	villainCards := availableCount - missing
+	counts := make([]int, villainCount)
+
+	for i := range counts {
+		counts[i] = int(binomial(villainCards-i*holeCards, holeCards))
+	}
+
+	return counts
+}
+
+func binomial(n int, k int) float64 {
+	result := 1.0
+	for i := 0; i < k; i++ {
+		result *= float64(n-i) / float64(i+1)
+	}
+	return result
+}
+
 func handTypesMap() map[string]int {
 	htmap := map[string]int{}
 
@@ -144,7 +316,10 @@ func handTypesMap() map[string]int {
 	return htmap
 }
 
-func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []string, villainCount int) (Odds, error) {
+// Calculate returns the hero's odds against villains with random hands.
+// Dead cards, such as a mucked hand seen or cards exposed in a stud game,
+// are dealt to neither the board nor the villains.
+func (calc *OddsCalculator) Calculate(game Game, heroCards []deck.Card, communityCards []deck.Card, villainCount int, deadCards ...deck.Card) (Odds, error) {
 
 	resultAccumulator := Odds{
 		Hero: handTypesMap(),
@@ -153,26 +328,23 @@ func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []s
 	if villainCount < 1 || villainCount > 9 {
 		return resultAccumulator, fmt.Errorf("between 1 and 9 villains supported")
 	}
-	// const maxSampleSize = 100000
-	// const minSampleSize = 1000
-	// if sampleSize < minSampleSize || sampleSize > maxSampleSize {
-	// 	return resultAccumulator, fmt.Errorf("sample size between %d and %d is allowed", minSampleSize, maxSampleSize)
-	// }
-
-	hero, err := calc.deck.CardStringsToNumbers(heroStrings)
 
-	if err != nil {
-		return resultAccumulator, err
+	for _, c := range append(append(append([]deck.Card{}, heroCards...), communityCards...), deadCards...) {
+		if !calc.deckFor(game).Contains(c) {
+			return resultAccumulator, fmt.Errorf("%s is not in the %s deck", c, game)
+		}
 	}
 
-	community, err := calc.deck.CardStringsToNumbers(communityStrings)
+	hero := deck.Numbers(heroCards)
+	community := deck.Numbers(communityCards)
+	dead := deck.Numbers(deadCards)
 
-	if err != nil {
-		return resultAccumulator, err
+	if game.isStud() || game.isDraw() {
+		return calc.calculateStud(game, hero, community, dead, villainCount)
 	}
 
-	if len(hero) != 2 {
-		return resultAccumulator, fmt.Errorf("please provide 2 hole cards")
+	if len(hero) != game.holeCardsCount() {
+		return resultAccumulator, fmt.Errorf("please provide %d hole cards for %s", game.holeCardsCount(), game)
 	}
 
 	acceptedCommunityCount := map[int]struct{}{
@@ -185,17 +357,22 @@ func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []s
 		return resultAccumulator, fmt.Errorf("please provide 0 or 3 or 4 or 5 community cards")
 	}
 
-	if duplicate, found := calc.hasDuplicates(hero, community); found {
+	if duplicate, found := calc.hasDuplicates(hero, community, dead); found {
 		return resultAccumulator, fmt.Errorf("found more than one " + duplicate)
 	}
 
-	// memoKey := calc.getMemoKey(hero, community, villainCount)
-	// fmt.Println("Memo Key: " + memoKey)
+	// Exact results are the same for every spot that only differs by suits.
+	// Dead cards aren't in the key, so spots with dead cards aren't memoized.
+	memoKey := ""
+	if len(dead) == 0 {
+		memoKey = calc.getMemoKey(game, hero, community, villainCount)
 
-	// if cached, ok := calc.readFromMemo(memoKey); ok && cached.sampleSize >= sampleSize {
-	// 	fmt.Println("Serving cached")
-	// 	return cached.result, nil
-	// }
+		if cached, ok := calc.readFromMemo(memoKey); ok {
+			result := cached.result.clone()
+			result.Run.Milliseconds = 0
+			return result, nil
+		}
+	}
 
 	// if communityCount == 0 && sampleSize > 5000 {
 	// 	fmt.Println("Waiting to compute expensive preflop " + memoKey)
@@ -208,11 +385,16 @@ func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []s
 	// 	}
 	// }
 
-	deck := calc.deck.AllNumberValues()
-	knownToCommunity := append(hero, community...)
-	availableToCommunity := list.Filter(deck, func(dc uint8) bool {
-		return !list.Includes(knownToCommunity, dc)
-	})
+	availableToCommunity, err := calc.available(game, hero, community, dead)
+
+	if err != nil {
+		return resultAccumulator, err
+	}
+
+	if len(availableToCommunity) < remainingCommunityCardsCount(community)+villainCount*game.holeCardsCount() {
+		return resultAccumulator, fmt.Errorf("not enough cards left to deal %d villains", villainCount)
+	}
+
 	availableToCommunityCount := uint8(len(availableToCommunity))
 	remainingCommunityCount := uint8(remainingCommunityCardsCount(community))
 	//
@@ -225,86 +407,123 @@ func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []s
 		return resultAccumulator, err
 	}
 
-	communityCombosSamplesTargetCount := 100 * 1000
-	totalTestsDesired := float64(communityCombosSamplesTargetCount) * 20000.0
+	budget := calc.options.newBudget()
+	totalTestsDesired := calc.options.showDownsDesired(game.totalTestsDesired())
+	communityCombosSamplesTargetCount := int(math.Min(100*1000, totalTestsDesired))
 	actualCommunityCombosSampleCount := combinationsSampler.Configure(allCommunityCombosCount, communityCombosSamplesTargetCount)
 
-	desiredSamplesPerVillain := int(math.Pow(totalTestsDesired/float64(actualCommunityCombosSampleCount), 1.0/float64(villainCount)))
+	desiredSamplesPerVillain := int(math.Max(1, math.Pow(totalTestsDesired/float64(actualCommunityCombosSampleCount), 1.0/float64(villainCount))))
 	communityCombinationsReadjustedTargetCount := totalTestsDesired / math.Pow(float64(desiredSamplesPerVillain), float64(villainCount))
 	fmt.Printf("%d villains\n", villainCount)
 	fmt.Printf("Desired Samples Per Villain %d\n", desiredSamplesPerVillain)
 
 	actualCommunityCombosSampleReadjustedCount := combinationsSampler.Configure(allCommunityCombosCount, int(communityCombinationsReadjustedTargetCount))
-	fmt.Printf("Community combinations count %d\n", actualCommunityCombosSampleReadjustedCount)
 
-	if err != nil {
-		return resultAccumulator, err
+	villainCombosCounts := villainCombosCounts(game, len(availableToCommunity), int(remainingCommunityCount), villainCount)
+	exactShowDowns := float64(allCommunityCombosCount)
+	for _, count := range villainCombosCounts {
+		exactShowDowns *= float64(count)
 	}
 
-	remainingCommuntiyCombinationsIndexChannel := make(chan int32, actualCommunityCombosSampleReadjustedCount)
-	workerCount := runtime.NumCPU()
-	results := make(chan showDownResults, workerCount)
+	if exactShowDowns <= totalTestsDesired {
+		// Few enough showdowns to play them all.
+		desiredSamplesPerVillain = villainCombosCounts[0]
+		actualCommunityCombosSampleReadjustedCount = combinationsSampler.Configure(allCommunityCombosCount, allCommunityCombosCount)
+	}
+	resultAccumulator.Exact = actualCommunityCombosSampleReadjustedCount == allCommunityCombosCount && desiredSamplesPerVillain >= villainCombosCounts[0]
 
-	fmt.Printf("Worker count: %d\n", workerCount)
+	showDownsPerCommunityCombo := 1
+	for _, count := range villainCombosCounts {
+		if count > desiredSamplesPerVillain {
+			count = desiredSamplesPerVillain
+		}
+		showDownsPerCommunityCombo *= count
+	}
+	planned := actualCommunityCombosSampleReadjustedCount * showDownsPerCommunityCombo
+	fmt.Printf("Community combinations count %d\n", actualCommunityCombosSampleReadjustedCount)
 
-	for w := 0; w < workerCount; w++ {
-		go calc.showDown(hero, community, availableToCommunity, villainCount, desiredSamplesPerVillain,
-			allRemainingCommunityCombinations, remainingCommuntiyCombinationsIndexChannel, results)
+	if err != nil {
+		return resultAccumulator, err
 	}
-	for index := combinationsSampler.Next(); index > -1; index = combinationsSampler.Next() {
-		remainingCommuntiyCombinationsIndexChannel <- index
+
+	nextCommunityComboIndex := combinationsSampler.Next
+	if resultAccumulator.Exact && calc.options.stopsEarly() {
+		// The calculation can stop before the last runout, the runouts go in
+		// a random order for the ones played to be a fair sample.
+		order := rand.Perm(allCommunityCombosCount)
+		nextCommunityComboIndex = func() int32 {
+			index := order[0]
+			order = order[1:]
+			return int32(index)
+		}
 	}
 
-	close(remainingCommuntiyCombinationsIndexChannel)
-	//combinationsSampler.PrintDuplicateCount("main")
-	//combinationsSampler.Print()
-	fmt.Println("closed communtiyCombinationsChannel")
+	workerCount := runtime.NumCPU()
+	fmt.Printf("Worker count: %d\n", workerCount)
 
+	exact := resultAccumulator.Exact
+	firstVillainSampleSize := math.Min(float64(desiredSamplesPerVillain), float64(villainCombosCounts[0]))
 	resultAccumulator.TieVillainCounts = map[int]int{}
 
 	// villainHandsFaced := make([]int, len(calc.allPossiblePairs))
 	// villainHandsLostTo := make([]int, len(calc.allPossiblePairs))
 	// villainHandsTiedWith := make([]int, len(calc.allPossiblePairs))
 
-	for i := 0; i < workerCount; i++ {
+	for _, roundSize := range budget.rounds(actualCommunityCombosSampleReadjustedCount) {
 
-		r := <-results
-		resultAccumulator.Totals.Total += r.total
-		resultAccumulator.Totals.Win += r.win
-		resultAccumulator.Totals.Lose += r.lose
-		resultAccumulator.Totals.Tie += r.tie
+		remainingCommuntiyCombinationsIndexChannel := make(chan int32, roundSize)
+		results := make(chan showDownResults, workerCount)
 
-		// if resultAccumulator.Total%100000 == 0 {
-		// 	fmt.Println(resultAccumulator.Total)
-		// }
-		for i, handType := range handevaluator.HandTypes() {
+		for w := 0; w < workerCount; w++ {
+			go calc.showDown(game, hero, community, availableToCommunity, villainCount, desiredSamplesPerVillain,
+				allRemainingCommunityCombinations, remainingCommuntiyCombinationsIndexChannel, budget, results)
+		}
 
-			resultAccumulator.Hero[handType] += r.hero[i]
+		for i := 0; i < roundSize; i++ {
+			remainingCommuntiyCombinationsIndexChannel <- nextCommunityComboIndex()
 		}
 
-		for k, count := range r.tieVillainCounts {
+		close(remainingCommuntiyCombinationsIndexChannel)
+		//combinationsSampler.PrintDuplicateCount("main")
+		//combinationsSampler.Print()
+		fmt.Println("closed communtiyCombinationsChannel")
 
-			resultAccumulator.TieVillainCounts[k] += count
-		}
+		for i := 0; i < workerCount; i++ {
+
+			r := <-results
+			resultAccumulator.add(r)
+
+			// if resultAccumulator.Total%100000 == 0 {
+			// 	fmt.Println(resultAccumulator.Total)
+			// }
+
+			// for k, count := range r.villainHandsFaced {
+
+			// 	villainHandsFaced[k] += count
+			// }
+			// for k, count := range r.villainHandsLostTo {
 
-		// for k, count := range r.villainHandsFaced {
+			// 	villainHandsLostTo[k] += count
+			// }
 
-		// 	villainHandsFaced[k] += count
-		// }
-		// for k, count := range r.villainHandsLostTo {
+			// for k, count := range r.villainHandsTiedWith {
 
-		// 	villainHandsLostTo[k] += count
-		// }
+			// 	villainHandsTiedWith[k] += count
+			// }
 
-		// for k, count := range r.villainHandsTiedWith {
+		}
 
-		// 	villainHandsTiedWith[k] += count
-		// }
+		resultAccumulator.Run.Rounds++
+		resultAccumulator.setProbabilities(game)
+		resultAccumulator.setPrecision(resultAccumulator.clusters.standardErrors(float64(allCommunityCombosCount), firstVillainSampleSize, float64(villainCombosCounts[0])))
 
+		if budget.expired() || budget.precise(resultAccumulator.Precision, exact && resultAccumulator.communityCombos == allCommunityCombosCount) {
+			break
+		}
 	}
-	resultAccumulator.Probabilities.Win = 100 * float32(resultAccumulator.Totals.Win) / float32(resultAccumulator.Totals.Total)
-	resultAccumulator.Probabilities.Lose = 100 * float32(resultAccumulator.Totals.Lose) / float32(resultAccumulator.Totals.Total)
-	resultAccumulator.Probabilities.Tie = 100 * float32(resultAccumulator.Totals.Tie) / float32(resultAccumulator.Totals.Total)
+
+	resultAccumulator.Exact = exact && resultAccumulator.communityCombos == allCommunityCombosCount
+	resultAccumulator.Run = budget.run(resultAccumulator.Run.Rounds, planned, resultAccumulator.communityCombos < actualCommunityCombosSampleReadjustedCount, resultAccumulator.Precision, resultAccumulator.Exact)
 
 	// resultAccumulator.HandComparisions = make([]HandComparision, 0)
 	// for k, handsFaced := range villainHandsFaced {
@@ -324,17 +543,9 @@ func (calc *OddsCalculator) Calculate(heroStrings []string, communityStrings []s
 	// }
 	fmt.Println("Odds evaluated")
 
-	// calc.memoMutex.Lock()
-	// defer calc.memoMutex.Unlock()
-	// if cached, ok := calc.memo[memoKey]; ok && cached.result.Total > resultAccumulator.Total {
-	// 	fmt.Println("result discard " + memoKey)
-	// 	return cached.result, nil
-	// }
-
-	// calc.memo[memoKey] = memoizedValue{
-	// 	result:     resultAccumulator,
-	// 	sampleSize: sampleSize,
-	// }
+	if resultAccumulator.Exact && memoKey != "" {
+		calc.writeToMemo(memoKey, resultAccumulator)
+	}
 
 	return resultAccumulator, nil
 }
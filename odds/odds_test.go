@@ -0,0 +1,122 @@
+package odds
+
+import (
+	"holdem/combinations"
+	"holdem/deck"
+	"holdem/handevaluator"
+	"testing"
+)
+
+func newTestCalculator() OddsCalculator {
+	return NewCalculator(handevaluator.NewCactusKev(), combinations.New(), deck.New())
+}
+
+func cards(t *testing.T, s string) []deck.Card {
+	t.Helper()
+	parsed, err := deck.ParseCards([]string{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// On 2c7d9hJsKc aces lose to 15 sets, 90 two pairs and 32 straights, tie
+// with the last aces and beat the other 852 of the 990 villain hands.
+func TestExactRiver(t *testing.T) {
+
+	calc := newTestCalculator()
+	hero, board := cards(t, "AhAd"), cards(t, "2c7d9hJsKc")
+	expected := Totals{Total: 990, Win: 852, Lose: 137, Tie: 1}
+
+	random, err := calc.Calculate(Holdem, hero, board, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versus, err := calc.CalculateVersus(Request{Game: Holdem, Hero: hero, Community: board, Villains: []Villain{{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, o := range []Odds{random, versus} {
+		if !o.Exact {
+			t.Errorf("river against a random hand isn't exact")
+		}
+		if o.Totals != expected {
+			t.Errorf("totals are %+v, expected %+v", o.Totals, expected)
+		}
+		if equity := float32(100 * (852 + 0.5) / 990); o.Equity != equity {
+			t.Errorf("equity is %v, expected %v", o.Equity, equity)
+		}
+	}
+}
+
+func TestExactIsRepeatable(t *testing.T) {
+
+	hero, board := cards(t, "AhKh"), cards(t, "QhJh2c")
+
+	// Every call gets a new calculator, so that exact results aren't served
+	// from the memo.
+	calculations := map[string]func() (Odds, error){
+		"random villain": func() (Odds, error) {
+			calc := newTestCalculator()
+			return calc.Calculate(Holdem, hero, board, 1)
+		},
+		"versus": func() (Odds, error) {
+			calc := newTestCalculator()
+			return calc.CalculateVersus(Request{Game: Holdem, Hero: hero, Community: board, Villains: []Villain{{}}})
+		},
+	}
+
+	for name, calculate := range calculations {
+		first, err := calculate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := calculate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !first.Exact || !second.Exact {
+			t.Errorf("%s: flop against a random hand isn't exact", name)
+		}
+		if first.Totals != second.Totals || first.Probabilities != second.Probabilities || first.Equity != second.Equity {
+			t.Errorf("%s: %+v %+v then %+v %+v", name, first.Totals, first.Probabilities, second.Totals, second.Probabilities)
+		}
+		if first.Totals.Total != 1070190 {
+			t.Errorf("%s: %d showdowns, expected every one of 1070190", name, first.Totals.Total)
+		}
+	}
+}
+
+func TestMemoServesSuitVariants(t *testing.T) {
+
+	calc := newTestCalculator()
+	expected := Totals{Total: 990, Win: 852, Lose: 137, Tie: 1}
+
+	first, err := calc.Calculate(Holdem, cards(t, "AhAd"), cards(t, "2c7d9hJsKc"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Hero["one pair"] = -1
+
+	// Hearts to spades, diamonds to clubs, clubs to hearts, spades to diamonds.
+	renamed, err := calc.Calculate(Holdem, cards(t, "AsAc"), cards(t, "2h7c9sJdKh"), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calc.memo) != 1 {
+		t.Errorf("the memo holds %d results, expected 1", len(calc.memo))
+	}
+	if renamed.Totals != expected || renamed.Hero["one pair"] != 990 {
+		t.Errorf("memoized totals are %+v with %d pairs", renamed.Totals, renamed.Hero["one pair"])
+	}
+
+	if _, err := calc.Calculate(Holdem, cards(t, "AhAd"), cards(t, "2c7d9hJsKc"), 1, cards(t, "Qh")...); err != nil {
+		t.Fatal(err)
+	}
+	if len(calc.memo) != 1 {
+		t.Errorf("a result with dead cards was memoized")
+	}
+}
@@ -0,0 +1,151 @@
+package odds
+
+import (
+	"fmt"
+	"time"
+)
+
+// budgetCheckInterval is how many showdowns sampling workers play between
+// looks at the clock.
+const budgetCheckInterval = 1000
+
+// firstRoundDivisor sets the first round of a calculation with a precision
+// target to this part of the planned work, every later round doubling the
+// work done.
+const firstRoundDivisor = 64
+
+// minFirstRound is the fewest runouts or showdowns a first round plays, so
+// that its precision is worth checking.
+const minFirstRound = 100
+
+// Options bound the work of a calculation, so that a quick preview and a
+// long study can cost differently. Zero values keep the defaults.
+type Options struct {
+	// MaxShowDowns caps how many showdowns are played.
+	MaxShowDowns int
+	// TimeBudget stops a calculation once it has run this long, returning
+	// the odds of the showdowns played so far.
+	TimeBudget time.Duration
+	// TargetPrecision stops a calculation once the 95% confidence interval
+	// of the hero's equity reaches no further than this many percentage
+	// points either side. The work is then done in rounds, checking the
+	// precision after each.
+	TargetPrecision float64
+}
+
+// Run tells how much of a calculation was done. Totals count the showdowns
+// that were played.
+type Run struct {
+	// Planned is how many showdowns the calculation set out to play.
+	Planned int
+	Rounds  int
+	// OutOfTime is set when the time budget ran out before every planned
+	// showdown was played.
+	OutOfTime bool
+	// PrecisionReached is set when the target precision was met.
+	PrecisionReached bool
+	Milliseconds     int64
+}
+
+// WithOptions returns a calculator sharing this one's evaluators and memo
+// that calculates within the options.
+func (calc *OddsCalculator) WithOptions(options Options) (OddsCalculator, error) {
+
+	if options.MaxShowDowns < 0 {
+		return *calc, fmt.Errorf("the maximum number of showdowns can't be negative")
+	}
+
+	if options.TimeBudget < 0 {
+		return *calc, fmt.Errorf("the time budget can't be negative")
+	}
+
+	if options.TargetPrecision < 0 {
+		return *calc, fmt.Errorf("the target precision can't be negative")
+	}
+
+	c := *calc
+	c.options = options
+	return c, nil
+}
+
+// showDownsDesired caps a calculation's default number of showdowns.
+func (o Options) showDownsDesired(defaultCount float64) float64 {
+	if o.MaxShowDowns > 0 && float64(o.MaxShowDowns) < defaultCount {
+		return float64(o.MaxShowDowns)
+	}
+	return defaultCount
+}
+
+// stopsEarly tells whether a calculation can stop before the work planned.
+func (o Options) stopsEarly() bool {
+	return o.TimeBudget > 0 || o.TargetPrecision > 0
+}
+
+// budget holds the limits of one calculation as it runs.
+type budget struct {
+	options  Options
+	start    time.Time
+	deadline time.Time
+}
+
+func (o Options) newBudget() budget {
+	b := budget{options: o, start: time.Now()}
+	if o.TimeBudget > 0 {
+		b.deadline = b.start.Add(o.TimeBudget)
+	}
+	return b
+}
+
+func (b budget) expired() bool {
+	return !b.deadline.IsZero() && time.Now().After(b.deadline)
+}
+
+// rounds splits the planned runouts or showdowns into rounds: all of them
+// at once, or with a precision target a first round and rounds doubling
+// the work done so far.
+func (b budget) rounds(planned int) []int {
+
+	if b.options.TargetPrecision <= 0 {
+		return []int{planned}
+	}
+
+	size := planned / firstRoundDivisor
+	if size < minFirstRound {
+		size = minFirstRound
+	}
+
+	rounds := []int{}
+	for done := 0; done < planned; {
+		if size > planned-done {
+			size = planned - done
+		}
+		rounds = append(rounds, size)
+		done += size
+		size = done
+	}
+
+	return rounds
+}
+
+// precise tells whether the hero's equity is as precise as the target. A
+// standard error of 0 only counts for exact results, a sample too small to
+// measure its error isn't precise.
+func (b budget) precise(p Precision, exact bool) bool {
+	if b.options.TargetPrecision <= 0 || (p.Equity.StandardError == 0 && !exact) {
+		return false
+	}
+	return z95*float64(p.Equity.StandardError) <= b.options.TargetPrecision
+}
+
+// run reports a calculation that played rounds of the planned showdowns,
+// unfinished when it stopped before the end.
+func (b budget) run(rounds int, planned int, unfinished bool, precision Precision, exact bool) Run {
+	reached := b.precise(precision, exact)
+	return Run{
+		Planned:          planned,
+		Rounds:           rounds,
+		OutOfTime:        unfinished && !reached,
+		PrecisionReached: reached,
+		Milliseconds:     time.Since(b.start).Milliseconds(),
+	}
+}
@@ -0,0 +1,215 @@
+package odds
+
+import "math"
+
+// z95 is how many standard errors a 95% confidence interval spans on each
+// side of an estimate.
+const z95 = 1.96
+
+// Interval tells how precise an estimated percentage is. Exact results have
+// a standard error of 0.
+type Interval struct {
+	StandardError float32
+	// Low and High bound the 95% confidence interval.
+	Low  float32
+	High float32
+}
+
+// Precision holds the intervals of the win, tie and lose probabilities and of
+// the equity they go with.
+type Precision struct {
+	Win    Interval
+	Tie    Interval
+	Lose   Interval
+	Equity Interval
+}
+
+// Statistics measured on every showdown, in the order of Precision.
+const (
+	winStatistic = iota
+	tieStatistic
+	loseStatistic
+	equityStatistic
+	statisticsCount
+)
+
+func newInterval(percentage float32, standardError float64) Interval {
+	se := 100 * standardError
+	return Interval{
+		StandardError: float32(se),
+		Low:           float32(math.Max(0, float64(percentage)-z95*se)),
+		High:          float32(math.Min(100, float64(percentage)+z95*se)),
+	}
+}
+
+func newPrecision(probabilities Probabilities, equity float32, standardErrors [statisticsCount]float64) Precision {
+	return Precision{
+		Win:    newInterval(probabilities.Win, standardErrors[winStatistic]),
+		Tie:    newInterval(probabilities.Tie, standardErrors[tieStatistic]),
+		Lose:   newInterval(probabilities.Lose, standardErrors[loseStatistic]),
+		Equity: newInterval(equity, standardErrors[equityStatistic]),
+	}
+}
+
+// unitMoments sum the statistics of the units of a sample and their
+// squares.
+type unitMoments struct {
+	n       int
+	sum     [statisticsCount]float64
+	squares [statisticsCount]float64
+}
+
+func (m *unitMoments) add(values [statisticsCount]float64) {
+	m.n++
+	for i, value := range values {
+		m.sum[i] += value
+		m.squares[i] += value * value
+	}
+}
+
+func (m *unitMoments) mean() [statisticsCount]float64 {
+	var means [statisticsCount]float64
+	for i := range means {
+		means[i] = m.sum[i] / float64(m.n)
+	}
+	return means
+}
+
+// variance is the sample variance of the units, 0 with fewer than two.
+func (m *unitMoments) variance() [statisticsCount]float64 {
+	var variances [statisticsCount]float64
+	if m.n < 2 {
+		return variances
+	}
+	n := float64(m.n)
+	for i := range variances {
+		mean := m.sum[i] / n
+		variances[i] = math.Max(0, (m.squares[i]-n*mean*mean)/(n-1))
+	}
+	return variances
+}
+
+// clusterMoments sum the showdowns of a two stage sample: runouts drawn
+// from all the runouts, then hands of the first villain drawn for each
+// runout, with the other villains' hands drawn under them. Each runout is
+// a cluster whose units are the first villain's hands.
+type clusterMoments struct {
+	n int
+	// mean and meanSquares sum the cluster means and their squares.
+	mean        [statisticsCount]float64
+	meanSquares [statisticsCount]float64
+	// within sums the variances between the units of each cluster.
+	within [statisticsCount]float64
+}
+
+// add records a cluster made of units.
+func (m *clusterMoments) add(units unitMoments) {
+	m.n++
+	means, variances := units.mean(), units.variance()
+	for i := range means {
+		m.mean[i] += means[i]
+		m.meanSquares[i] += means[i] * means[i]
+		m.within[i] += variances[i]
+	}
+}
+
+func (m *clusterMoments) merge(other clusterMoments) {
+	m.n += other.n
+	for i := range m.mean {
+		m.mean[i] += other.mean[i]
+		m.meanSquares[i] += other.meanSquares[i]
+		m.within[i] += other.within[i]
+	}
+}
+
+// standardErrors estimates the standard errors of the statistics when the
+// clusters were drawn out of clusterCount and size units were drawn out of
+// the clusterSize of each, with the usual two stage estimator. The hands of
+// the villains after the first are not corrected for being drawn from a
+// finite number, which only overstates the errors. When every cluster was
+// played, as the single runout of a river, only the second stage counts.
+func (m clusterMoments) standardErrors(clusterCount float64, size float64, clusterSize float64) [statisticsCount]float64 {
+
+	var errors [statisticsCount]float64
+
+	n := float64(m.n)
+	sampled := 1 - n/clusterCount
+	villainsSampled := 1 - size/clusterSize
+
+	if m.n == 0 || (m.n < 2 && sampled > 0) {
+		return errors
+	}
+
+	for i := range errors {
+		between := 0.0
+		if m.n > 1 {
+			mean := m.mean[i] / n
+			between = math.Max(0, (m.meanSquares[i]-n*mean*mean)/(n-1))
+		}
+		within := m.within[i] / n
+		errors[i] = math.Sqrt(math.Max(0, sampled*between/n+(1-sampled)*villainsSampled*within/(n*size)))
+	}
+
+	return errors
+}
+
+// ratioMoments sum units of a sample that each hold a weighted total of
+// showdowns, to estimate the standard error of the statistics' weighted
+// means. A unit is a single showdown when hands are sampled, or every
+// showdown of one hand when an enumeration is cut short.
+type ratioMoments struct {
+	n       int
+	weight  float64
+	weights float64
+	// sum, squares and products sum each unit's statistic, its square and
+	// its product with the unit's weight.
+	sum      [statisticsCount]float64
+	squares  [statisticsCount]float64
+	products [statisticsCount]float64
+}
+
+func (m *ratioMoments) add(sums [statisticsCount]float64, weight float64) {
+	m.n++
+	m.weight += weight
+	m.weights += weight * weight
+	for i, y := range sums {
+		m.sum[i] += y
+		m.squares[i] += y * y
+		m.products[i] += y * weight
+	}
+}
+
+func (m *ratioMoments) merge(other ratioMoments) {
+	m.n += other.n
+	m.weight += other.weight
+	m.weights += other.weights
+	for i := range m.sum {
+		m.sum[i] += other.sum[i]
+		m.squares[i] += other.squares[i]
+		m.products[i] += other.products[i]
+	}
+}
+
+// standardErrors estimates the standard errors of the weighted means when
+// the units were drawn out of unitCount, infinite when drawn with
+// replacement.
+func (m ratioMoments) standardErrors(unitCount float64) [statisticsCount]float64 {
+
+	var errors [statisticsCount]float64
+
+	if m.n < 2 || m.weight == 0 {
+		return errors
+	}
+
+	n := float64(m.n)
+	sampled := 1 - n/unitCount
+	meanWeight := m.weight / n
+
+	for i := range errors {
+		ratio := m.sum[i] / m.weight
+		residuals := math.Max(0, (m.squares[i]-2*ratio*m.products[i]+ratio*ratio*m.weights)/(n-1))
+		errors[i] = math.Sqrt(math.Max(0, sampled*residuals/(n*meanWeight*meanWeight)))
+	}
+
+	return errors
+}
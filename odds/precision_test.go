@@ -0,0 +1,90 @@
+package odds
+
+import (
+	"holdem/deck"
+	"math"
+	"testing"
+)
+
+func TestExactHasNoError(t *testing.T) {
+
+	calc := newTestCalculator()
+	o, err := calc.Calculate(Holdem, cards(t, "AhAd"), cards(t, "2c7d9hJsKc"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !o.Exact {
+		t.Fatalf("river against two random hands isn't exact")
+	}
+	for _, interval := range []Interval{o.Precision.Win, o.Precision.Tie, o.Precision.Lose, o.Precision.Equity} {
+		if interval.StandardError != 0 {
+			t.Errorf("exact result has a standard error of %v", interval.StandardError)
+		}
+	}
+}
+
+// The reported standard error of sampled spots should match the spread of
+// the equity over repeated runs, whichever stages are sampled.
+func TestStandardErrorMatchesSpread(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("repeats sampled calculations")
+	}
+
+	const runs = 20
+
+	spots := []struct {
+		name         string
+		hero         string
+		board        string
+		villains     int
+		maxShowDowns int
+	}{
+		// Every runout, hands of the villains sampled under each.
+		{"flop", "AhKd", "Qs7c2d", 2, 100000},
+		// Runouts sampled, then hands of the villains under each.
+		{"preflop", "AhKd", "", 2, 400000},
+		// A single runout, hands of the villains sampled.
+		{"river", "AhKd", "Qs7c2d3h9s", 2, 200000},
+	}
+
+	base := newTestCalculator()
+
+	for _, spot := range spots {
+		calc, err := base.WithOptions(Options{MaxShowDowns: spot.maxShowDowns})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var board []deck.Card
+		if spot.board != "" {
+			board = cards(t, spot.board)
+		}
+
+		var sum, squares, reported float64
+		for i := 0; i < runs; i++ {
+			o, err := calc.Calculate(Holdem, cards(t, spot.hero), board, spot.villains)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if o.Exact {
+				t.Fatalf("%s: expected a sampled result", spot.name)
+			}
+			equity := float64(o.Equity)
+			sum += equity
+			squares += equity * equity
+			reported += float64(o.Precision.Equity.StandardError)
+		}
+
+		mean := sum / runs
+		spread := math.Sqrt((squares - runs*mean*mean) / (runs - 1))
+		reported /= runs
+		t.Logf("%s: reported standard error %.4f, spread over runs %.4f", spot.name, reported, spread)
+
+		// With 20 runs the spread itself is known to about 16%.
+		if ratio := reported / spread; ratio < 0.6 || ratio > 1.7 {
+			t.Errorf("%s: the reported standard error is %.2f times the spread", spot.name, ratio)
+		}
+	}
+}
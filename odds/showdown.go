@@ -15,14 +15,31 @@ type showDownResults struct {
 	tie              int
 	tieVillainCounts map[int]int
 	hero             []int
+	hiLo             HiLoTotals
+	potShare         float64
+	// communityCombos counts the runouts played.
+	communityCombos int
+	clusters        clusterMoments
 	// villainHandsFaced    []int
 	// villainHandsLostTo   []int
 	// villainHandsTiedWith []int
 }
 
+func newShowDownResults() showDownResults {
+	return showDownResults{
+		total:            0,
+		win:              0,
+		tie:              0,
+		lose:             0,
+		tieVillainCounts: map[int]int{},
+		hero:             make([]int, len(handevaluator.HandTypes())),
+	}
+}
+
 type villain struct {
 	cardsAvailable []uint8
 	combinations   [][]uint8
+	hand           []uint8
 	sampler        slicesampler.Sampler
 	sampleSize     int
 	lossMultiplier int
@@ -30,21 +47,25 @@ type villain struct {
 }
 
 type showDown struct {
+	game                       Game
 	hero                       []uint8
 	communityKnown             []uint8
 	availableToCommunity       []uint8
 	communityCombinations      [][]uint8
 	communityCombinationIndex  <-chan int32
 	results                    chan<- showDownResults
-	evaluator                  handevaluator.HandEvaluator
+	evaluator                  handevaluator.Evaluator
 	combinations               combinations.Combinations
 	reusableRemainingCommunity []uint8
+	reusableBoard              []uint8
 	cumulativeResults          showDownResults
 	villains                   []villain
+	villainValues              []handValues
 	totalPerCombo              int
 }
 
 func (calc *OddsCalculator) showDown(
+	game Game,
 	hero []uint8,
 	communityKnown []uint8,
 	availableToCommunity []uint8,
@@ -52,36 +73,36 @@ func (calc *OddsCalculator) showDown(
 	desiredSamplesPerVillain int,
 	communityCombinations [][]uint8,
 	communityCombinationIndex <-chan int32,
+	budget budget,
 	results chan<- showDownResults) {
 
 	showDown := showDown{
+		game:                       game,
 		hero:                       hero,
 		communityKnown:             communityKnown,
 		availableToCommunity:       availableToCommunity,
 		communityCombinations:      communityCombinations,
 		communityCombinationIndex:  communityCombinationIndex,
 		results:                    results,
-		evaluator:                  calc.evaluator,
+		evaluator:                  calc.evaluatorFor(game),
 		combinations:               calc.combinations,
 		reusableRemainingCommunity: make([]uint8, remainingCommunityCardsCount(communityKnown)),
+		reusableBoard:              make([]uint8, 5),
 		villains:                   make([]villain, villainCount),
-		cumulativeResults: showDownResults{
-			total:            0,
-			win:              0,
-			tie:              0,
-			lose:             0,
-			tieVillainCounts: map[int]int{},
-			hero:             make([]int, len(handevaluator.HandTypes())),
-		},
+		villainValues:              make([]handValues, villainCount),
+		cumulativeResults:          newShowDownResults(),
 	}
 
 	cardsAvailableToVillain := len(availableToCommunity) - remainingCommunityCardsCount(communityKnown)
 	showDown.totalPerCombo = 1
 
+	holeCardsCount := game.holeCardsCount()
+
 	for i := range showDown.villains {
-		combinations, err := calc.combinations.Get(uint8(cardsAvailableToVillain), 2)
+		combinations, err := calc.combinations.Get(uint8(cardsAvailableToVillain), uint8(holeCardsCount))
 		showDown.villains[i].cardsAvailable = make([]uint8, cardsAvailableToVillain)
-		cardsAvailableToVillain -= 2
+		showDown.villains[i].hand = make([]uint8, holeCardsCount)
+		cardsAvailableToVillain -= holeCardsCount
 
 		if err != nil {
 			panic(err.Error())
@@ -100,6 +121,9 @@ func (calc *OddsCalculator) showDown(
 
 	for communityComboIndex := range communityCombinationIndex {
 		showDown.showDownForCommunityComboIndex(communityComboIndex)
+		if budget.expired() {
+			break
+		}
 	}
 
 	results <- showDown.cumulativeResults
@@ -108,17 +132,47 @@ func (calc *OddsCalculator) showDown(
 func (sd *showDown) showDownForCommunityComboIndex(communityComboIndex int32) {
 
 	list.CopyValuesAtIndexes(sd.reusableRemainingCommunity, sd.availableToCommunity, sd.communityCombinations[communityComboIndex])
-	partialEvaluation := sd.evaluator.PartialEvaluation(sd.communityKnown, sd.reusableRemainingCommunity)
+	copy(sd.reusableBoard[copy(sd.reusableBoard, sd.communityKnown):], sd.reusableRemainingCommunity)
+	highEvaluation := sd.game.boardEvaluation(sd.evaluator, sd.reusableBoard)
 
-	heroValue, heroHandTypeIndex := partialEvaluation.Eval(sd.hero[0], sd.hero[1])
+	heroValue, heroHandTypeIndex := highEvaluation.Eval(sd.hero)
 
 	if heroHandTypeIndex == handevaluator.InvalidHandIndex {
 		panic("invalid hand for hero")
 	}
 
+	var lowEvaluation boardEvaluation
+	heroValues := handValues{high: heroValue}
+	if sd.game.isHiLo() {
+		lowEvaluation = sd.game.lowBoardEvaluation(sd.reusableBoard)
+		heroValues.low, _ = lowEvaluation.Eval(sd.hero)
+	}
+
 	showDownsWon := 0
 	showDownsTied := 0
 	showDownsLost := 0
+	tiedShare := 0.0
+
+	// Every hand of the first villain, with the hands of the others drawn
+	// under it, is a unit of the runout's cluster.
+	var units unitMoments
+	var unitStart [statisticsCount]float64
+	unitStarted := false
+	statistics := func() [statisticsCount]float64 {
+		s := sd.cumulativeResults.statistics()
+		s[winStatistic] += float64(showDownsWon)
+		s[tieStatistic] += float64(showDownsTied)
+		s[loseStatistic] += float64(showDownsLost)
+		s[equityStatistic] += float64(showDownsWon) + tiedShare
+		return s
+	}
+	addUnit := func() {
+		s := statistics()
+		for i := range s {
+			s[i] = (s[i] - unitStart[i]) / float64(sd.villains[0].lossMultiplier)
+		}
+		units.add(s)
+	}
 
 	list.CopyValuesNotAtIndexes(sd.villains[0].cardsAvailable, sd.availableToCommunity, sd.communityCombinations[communityComboIndex])
 	lastVillainIndex := len(sd.villains) - 1
@@ -126,43 +180,79 @@ func (sd *showDown) showDownForCommunityComboIndex(communityComboIndex int32) {
 	for vi := 0; vi > -1; vi-- {
 
 		for viComboIndex := sd.villains[vi].sampler.Next(); viComboIndex > -1; viComboIndex = sd.villains[vi].sampler.Next() {
-			currentViCombo := sd.villains[vi].combinations[viComboIndex]
-			viCardA, viCardB := sd.villains[vi].cardsAvailable[currentViCombo[0]], sd.villains[vi].cardsAvailable[currentViCombo[1]]
-			villainValue, villainHandTypeIndex := partialEvaluation.Eval(viCardA, viCardB)
+			if vi == 0 {
+				if unitStarted {
+					addUnit()
+				}
+				unitStart = statistics()
+				unitStarted = true
+			}
 
-			currentTieCount := sd.villains[vi].tieCount
-			switch {
+			currentViCombo := sd.villains[vi].combinations[viComboIndex]
+			list.CopyValuesAtIndexes(sd.villains[vi].hand, sd.villains[vi].cardsAvailable, currentViCombo)
+			villainValue, villainHandTypeIndex := highEvaluation.Eval(sd.villains[vi].hand)
 
-			case villainHandTypeIndex == handevaluator.InvalidHandIndex:
+			if villainHandTypeIndex == handevaluator.InvalidHandIndex {
 				panic(fmt.Sprintf("invalid hand for villain %d", vi+1))
-			case villainValue > heroValue:
-				showDownsLost += sd.villains[vi].lossMultiplier
-				continue
-			case villainValue == heroValue:
-				currentTieCount++
-			default:
 			}
 
-			if vi == lastVillainIndex {
+			currentTieCount := sd.villains[vi].tieCount
 
-				if currentTieCount == 0 {
-					showDownsWon++
-				} else {
-					showDownsTied++
-					sd.cumulativeResults.tieVillainCounts[currentTieCount] += 1
+			if lowEvaluation != nil {
+				// Every villain's hands matter until the end, a villain with
+				// the best high hand can still lose the low half.
+				sd.villainValues[vi].high = villainValue
+				sd.villainValues[vi].low, _ = lowEvaluation.Eval(sd.villains[vi].hand)
+
+				if vi == lastVillainIndex {
+					sd.cumulativeResults.settle(heroValues, sd.villainValues)
+					continue
+				}
+			} else {
+				switch {
+				case villainValue > heroValue:
+					showDownsLost += sd.villains[vi].lossMultiplier
+					continue
+				case villainValue == heroValue:
+					currentTieCount++
+				default:
+				}
+
+				if vi == lastVillainIndex {
+
+					if currentTieCount == 0 {
+						showDownsWon++
+					} else {
+						showDownsTied++
+						share := 1 / float64(currentTieCount+1)
+						tiedShare += share
+						sd.cumulativeResults.tieVillainCounts[currentTieCount] += 1
+					}
+					continue
 				}
-				continue
 			}
+
 			vi += 1
 			list.CopyValuesNotAtIndexes(sd.villains[vi].cardsAvailable, sd.villains[vi-1].cardsAvailable, currentViCombo)
 			sd.villains[vi].tieCount = currentTieCount
 		}
 
 	}
+	if unitStarted {
+		addUnit()
+	}
 
 	sd.cumulativeResults.total += sd.totalPerCombo
+	sd.cumulativeResults.communityCombos++
 	sd.cumulativeResults.win += showDownsWon
 	sd.cumulativeResults.tie += showDownsTied
 	sd.cumulativeResults.lose += showDownsLost
+	sd.cumulativeResults.potShare += float64(showDownsWon) + tiedShare
 	sd.cumulativeResults.hero[heroHandTypeIndex] += sd.totalPerCombo
+	sd.cumulativeResults.clusters.add(units)
+}
+
+// statistics returns the sums of the statistics of the showdowns recorded.
+func (r *showDownResults) statistics() [statisticsCount]float64 {
+	return [statisticsCount]float64{float64(r.win), float64(r.tie), float64(r.lose), r.potShare}
 }
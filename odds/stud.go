@@ -0,0 +1,208 @@
+package odds
+
+import (
+	"fmt"
+	"holdem/handevaluator"
+	"holdem/list"
+	"math"
+	"math/rand"
+	"runtime"
+	"time"
+)
+
+// studShowDownsDesired is how many random deals a stud calculation plays out.
+const studShowDownsDesired = 200000
+
+// maxStudVillains keeps every player's seven cards within one deck.
+const maxStudVillains = 6
+
+// calculateStud plays out random deals of a stud or draw game: the hero is
+// dealt the rest of their seven cards, or five in a draw game, and every
+// villain as many random cards. In draw games the hero's cards are the
+// first of their five and every player then draws three times, keeping
+// cards by deuceToSevenKeeps. Dead cards are dealt to nobody.
+func (calc *OddsCalculator) calculateStud(game Game, hero []uint8, community []uint8, dead []uint8, villainCount int) (Odds, error) {
+
+	resultAccumulator := Odds{
+		Hero:             handTypesMap(),
+		TieVillainCounts: map[int]int{},
+	}
+
+	if len(community) != 0 {
+		return resultAccumulator, fmt.Errorf("%s has no community cards", game)
+	}
+
+	minimumHeroCards := 3
+	if game.isDraw() {
+		minimumHeroCards = 0
+	}
+
+	if len(hero) < minimumHeroCards || len(hero) > game.holeCardsCount() {
+		return resultAccumulator, fmt.Errorf("please provide between %d and %d cards for %s", minimumHeroCards, game.holeCardsCount(), game)
+	}
+
+	if game.isStud() && villainCount > maxStudVillains {
+		return resultAccumulator, fmt.Errorf("between 1 and %d villains supported for %s", maxStudVillains, game)
+	}
+
+	if duplicate, found := calc.hasDuplicates(hero, dead); found {
+		return resultAccumulator, fmt.Errorf("found more than one " + duplicate)
+	}
+
+	available, err := calc.available(game, hero, dead)
+
+	if err != nil {
+		return resultAccumulator, err
+	}
+
+	if len(available) < game.holeCardsCount()-len(hero)+villainCount*game.holeCardsCount() {
+		return resultAccumulator, fmt.Errorf("not enough cards left to deal %d villains", villainCount)
+	}
+
+	budget := calc.options.newBudget()
+	showDownsDesired := int(calc.options.showDownsDesired(studShowDownsDesired))
+	workerCount := runtime.NumCPU()
+	results := make(chan showDownResults, workerCount)
+
+	for _, roundSize := range budget.rounds(showDownsDesired) {
+
+		for w := 0; w < workerCount; w++ {
+			showDowns := roundSize / workerCount
+			if w < roundSize%workerCount {
+				showDowns++
+			}
+			seed := time.Now().UnixNano() + int64(w)
+			go calc.studShowDown(game, hero, available, villainCount, showDowns, seed, budget, results)
+		}
+
+		for i := 0; i < workerCount; i++ {
+			resultAccumulator.add(<-results)
+		}
+
+		resultAccumulator.Run.Rounds++
+		resultAccumulator.setProbabilities(game)
+		resultAccumulator.setPrecision(resultAccumulator.clusters.standardErrors(math.Inf(1), 1, 1))
+
+		if budget.expired() || budget.precise(resultAccumulator.Precision, false) {
+			break
+		}
+	}
+
+	resultAccumulator.Run = budget.run(resultAccumulator.Run.Rounds, showDownsDesired, resultAccumulator.Totals.Total < showDownsDesired, resultAccumulator.Precision, false)
+
+	return resultAccumulator, nil
+}
+
+func (calc *OddsCalculator) studShowDown(
+	game Game,
+	heroKnown []uint8,
+	available []uint8,
+	villainCount int,
+	showDowns int,
+	seed int64,
+	budget budget,
+	results chan<- showDownResults) {
+
+	rGen := rand.New(rand.NewSource(seed))
+	cardsPerHand := game.holeCardsCount()
+	deck := list.Clone(available)
+	hero := make([]uint8, cardsPerHand)
+	heroMissing := cardsPerHand - len(heroKnown)
+	cardsNeeded := heroMissing + villainCount*cardsPerHand
+	evaluator := game.evaluator(calc.evaluator)
+	low := handevaluator.NewEightOrBetter()
+
+	villainValues := make([]handValues, villainCount)
+	cumulativeResults := newShowDownResults()
+
+	hands := [][]uint8{hero}
+	for v := 0; v < villainCount; v++ {
+		hands = append(hands, make([]uint8, cardsPerHand))
+	}
+	draw := newTripleDraw(rGen)
+
+	for s := 0; s < showDowns; s++ {
+
+		before := cumulativeResults.statistics()
+
+		if s > 0 && s%budgetCheckInterval == 0 && budget.expired() {
+			break
+		}
+
+		// Only the cards dealt need shuffling.
+		for i := 0; i < cardsNeeded; i++ {
+			j := i + rGen.Intn(len(deck)-i)
+			deck[i], deck[j] = deck[j], deck[i]
+		}
+
+		copy(hero, heroKnown)
+		copy(hero[len(heroKnown):], deck[:heroMissing])
+		for v, villain := range hands[1:] {
+			copy(villain, deck[heroMissing+v*cardsPerHand:])
+		}
+
+		if game.isDraw() {
+			draw.play(hands, deck[cardsNeeded:])
+		}
+
+		heroValue, heroHandTypeIndex := evaluator.Eval(hero...)
+
+		if heroHandTypeIndex == handevaluator.InvalidHandIndex {
+			panic("invalid hand for hero")
+		}
+
+		heroValues := handValues{high: heroValue}
+
+		for v, villain := range hands[1:] {
+			villainValues[v].high, _ = evaluator.Eval(villain...)
+			if game.isHiLo() {
+				villainValues[v].low, _ = low.Eval(villain...)
+			}
+		}
+
+		if game.isHiLo() {
+			heroValues.low, _ = low.Eval(hero...)
+			cumulativeResults.settle(heroValues, villainValues)
+		} else {
+			cumulativeResults.settleHigh(heroValue, villainValues)
+		}
+		cumulativeResults.total++
+		cumulativeResults.hero[heroHandTypeIndex]++
+		// Every deal is drawn on its own, a cluster of one showdown.
+		var deal unitMoments
+		statistics := cumulativeResults.statistics()
+		for i := range statistics {
+			statistics[i] -= before[i]
+		}
+		deal.add(statistics)
+		cumulativeResults.clusters.add(deal)
+	}
+
+	results <- cumulativeResults
+}
+
+// settleHigh records one showdown of a game won by the best value alone, the
+// high of each handValues.
+func (r *showDownResults) settleHigh(hero uint32, villains []handValues) {
+
+	winners := 1
+	for _, v := range villains {
+		switch {
+		case v.high > hero:
+			r.lose++
+			return
+		case v.high == hero:
+			winners++
+		}
+	}
+
+	if winners == 1 {
+		r.win++
+		r.potShare++
+		return
+	}
+	r.tie++
+	r.tieVillainCounts[winners-1]++
+	share := 1 / float64(winners)
+	r.potShare += share
+}
@@ -0,0 +1,890 @@
+package odds
+
+import (
+	"fmt"
+	"holdem/combinations"
+	"holdem/deck"
+	"holdem/handevaluator"
+	"holdem/list"
+	"holdem/ranges"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// versusShowDownsDesired is how many showdowns are sampled against villain
+// ranges when there are too many to play them all.
+const versusShowDownsDesired = 1000000
+
+// exactVersusLimit is the most showdowns played to enumerate every villain
+// hand and runout exactly.
+const exactVersusLimit = 2000000
+
+// rangeCombo is a hand a villain can hold and how often they hold it.
+type rangeCombo struct {
+	cards  [2]uint8
+	weight float64
+}
+
+// seat is a player whose hand is one of its combos.
+type seat struct {
+	combos []rangeCombo
+	// known is set when the player's hand is known, its cards then aren't
+	// available.
+	known bool
+	// cumulative holds the running total of the combo weights, to sample
+	// combos by weight.
+	cumulative []float64
+}
+
+func newSeat(combos []rangeCombo) seat {
+	s := seat{combos: combos, cumulative: make([]float64, len(combos))}
+	total := 0.0
+	for i, c := range combos {
+		total += c.weight
+		s.cumulative[i] = total
+	}
+	return s
+}
+
+// pick returns the index of a combo drawn by weight.
+func (s *seat) pick(rGen *rand.Rand) int {
+	target := rGen.Float64() * s.cumulative[len(s.cumulative)-1]
+	return sort.SearchFloat64s(s.cumulative, target)
+}
+
+// versus plays players holding known hands or hands from ranges against
+// each other. The first seat is the hero, or the first range when ranges
+// play against ranges.
+type versus struct {
+	game         Game
+	evaluator    handevaluator.Evaluator
+	combinations combinations.Combinations
+	community    []uint8
+	// available are the cards that aren't known, on the board or dead.
+	available []uint8
+	seats     []seat
+	options   Options
+}
+
+// playerResults are the weighted showdowns of one player.
+type playerResults struct {
+	win             float64
+	tie             float64
+	lose            float64
+	potShare        float64
+	potShareSquares float64
+}
+
+// versusResults are showdowns weighted by how often the players hold their
+// hands.
+type versusResults struct {
+	counts Totals
+	weight float64
+	exact  bool
+	run    Run
+	// enumerated is set when showdowns were enumerated rather than sampled,
+	// spread being the seat whose combos the workers shared out, and
+	// spreadCombos counts the ones enumerated.
+	enumerated   bool
+	spread       int
+	spreadCombos int
+	// players holds the hero's results, then every villain's.
+	players []playerResults
+	// units holds the players' results by unit of the sample, out of
+	// unitCount.
+	units     []ratioMoments
+	unitCount float64
+	// combos holds the results of each combo of the first seat, with the
+	// weight the combo was played with.
+	combos           []playerResults
+	comboWeights     []float64
+	tieVillainCounts map[int]int
+	hero             []int
+}
+
+func newVersusResults(playerCount int, comboCount int) versusResults {
+	return versusResults{
+		players:          make([]playerResults, playerCount),
+		units:            make([]ratioMoments, playerCount),
+		combos:           make([]playerResults, comboCount),
+		comboWeights:     make([]float64, comboCount),
+		tieVillainCounts: map[int]int{},
+		hero:             make([]int, len(handevaluator.HandTypes())),
+	}
+}
+
+func (p *playerResults) add(other playerResults) {
+	p.win += other.win
+	p.tie += other.tie
+	p.lose += other.lose
+	p.potShare += other.potShare
+	p.potShareSquares += other.potShareSquares
+}
+
+func (p *playerResults) minus(other playerResults) playerResults {
+	return playerResults{
+		win:             p.win - other.win,
+		tie:             p.tie - other.tie,
+		lose:            p.lose - other.lose,
+		potShare:        p.potShare - other.potShare,
+		potShareSquares: p.potShareSquares - other.potShareSquares,
+	}
+}
+
+func (p *playerResults) statistics() [statisticsCount]float64 {
+	return [statisticsCount]float64{p.win, p.tie, p.lose, p.potShare}
+}
+
+// standardErrors estimates the standard errors of results made of count
+// showdowns drawn on their own.
+func (p *playerResults) standardErrors(count float64) [statisticsCount]float64 {
+
+	var errors [statisticsCount]float64
+
+	if count < 2 {
+		return errors
+	}
+
+	// Win, tie and lose count each showdown as 0 or 1, their own squares.
+	squares := [statisticsCount]float64{p.win, p.tie, p.lose, p.potShareSquares}
+	for i, sum := range p.statistics() {
+		mean := sum / count
+		errors[i] = math.Sqrt(math.Max(0, (squares[i]-count*mean*mean)/(count-1)) / count)
+	}
+
+	return errors
+}
+
+// settle counts a showdown where the player's hand is worth value and
+// winners share the pot with hands worth best.
+func (p *playerResults) settle(value uint32, best uint32, winners int, weight float64) {
+	switch {
+	case value < best:
+		p.lose += weight
+	case winners == 1:
+		p.win += weight
+		p.potShare += weight
+		p.potShareSquares += weight
+	default:
+		share := 1 / float64(winners)
+		p.tie += weight
+		p.potShare += weight * share
+		p.potShareSquares += weight * share * share
+	}
+}
+
+// probabilities turns results weighing weight in all into percentages.
+func (p *playerResults) probabilities(weight float64) (Probabilities, float32) {
+	return Probabilities{
+		Win:  float32(100 * p.win / weight),
+		Tie:  float32(100 * p.tie / weight),
+		Lose: float32(100 * p.lose / weight),
+	}, float32(100 * p.potShare / weight)
+}
+
+func (r *versusResults) add(other versusResults) {
+	r.counts.Total += other.counts.Total
+	r.counts.Win += other.counts.Win
+	r.counts.Tie += other.counts.Tie
+	r.counts.Lose += other.counts.Lose
+	r.weight += other.weight
+	r.spreadCombos += other.spreadCombos
+	for i, p := range other.players {
+		r.players[i].add(p)
+		r.units[i].merge(other.units[i])
+	}
+	for i, p := range other.combos {
+		r.combos[i].add(p)
+		r.comboWeights[i] += other.comboWeights[i]
+	}
+	for k, count := range other.tieVillainCounts {
+		r.tieVillainCounts[k] += count
+	}
+	for i, count := range other.hero {
+		r.hero[i] += count
+	}
+}
+
+// odds reports the results. Totals count showdowns while Probabilities
+// weigh them by how often the villains hold their hands.
+func (r *versusResults) odds(players []PlayerOdds) Odds {
+
+	o := Odds{
+		Totals:           r.counts,
+		TieVillainCounts: r.tieVillainCounts,
+		Hero:             handTypesMap(),
+		Exact:            r.exact,
+		Run:              r.run,
+	}
+
+	for i, handType := range handevaluator.HandTypes() {
+		o.Hero[handType] += r.hero[i]
+	}
+
+	for i := range r.players {
+		players[i].Probabilities, players[i].Equity = r.players[i].probabilities(r.weight)
+		players[i].Precision = r.precision(i)
+	}
+	o.Players = players
+
+	o.Probabilities = o.Players[0].Probabilities
+	o.Equity = o.Players[0].Equity
+	o.Precision = o.Players[0].Precision
+
+	return o
+}
+
+// precision tells how precise the odds of a player are.
+func (r *versusResults) precision(player int) Precision {
+	probabilities, equity := r.players[player].probabilities(r.weight)
+	return newPrecision(probabilities, equity, r.units[player].standardErrors(r.unitCount))
+}
+
+// snapshot copies the players' results into before and returns the weight
+// so far, to record the showdowns that follow as one unit.
+func (r *versusResults) snapshot(before []playerResults) float64 {
+	copy(before, r.players)
+	return r.weight
+}
+
+func (r *versusResults) addUnit(before []playerResults, weightBefore float64) {
+	for i := range r.players {
+		difference := r.players[i].minus(before[i])
+		r.units[i].add(difference.statistics(), r.weight-weightBefore)
+	}
+}
+
+// comboOdds reports the results of every combo of the first seat that was
+// played.
+func (r *versusResults) comboOdds(s seat) []ComboOdds {
+
+	combos := []ComboOdds{}
+
+	for i, c := range s.combos {
+		if r.comboWeights[i] == 0 {
+			continue
+		}
+
+		combo := ComboOdds{
+			Hand:      []deck.Card{deck.Card(c.cards[0]), deck.Card(c.cards[1])},
+			Weight:    c.weight,
+			Frequency: float32(100 * r.comboWeights[i] / r.weight),
+		}
+		combo.Probabilities, combo.Equity = r.combos[i].probabilities(r.comboWeights[i])
+
+		var standardErrors [statisticsCount]float64
+		switch {
+		case !r.enumerated:
+			// Sampled showdowns all weigh 1.
+			standardErrors = r.combos[i].standardErrors(r.comboWeights[i])
+		case r.spread != 0:
+			// Only the spread seat's hands can be left out, the first seat
+			// holds a single combo.
+			standardErrors = r.units[0].standardErrors(r.unitCount)
+		}
+		combo.Precision = newPrecision(combo.Probabilities, combo.Equity, standardErrors)
+		combos = append(combos, combo)
+	}
+
+	return combos
+}
+
+// Villain is a player the hero faces. Their hand is Hand when it is known,
+// otherwise one from Range, otherwise random.
+type Villain struct {
+	Hand  []deck.Card
+	Range *ranges.Range
+}
+
+// Request describes a calculation against villains who can have known
+// hands or ranges.
+type Request struct {
+	Game      Game
+	Hero      []deck.Card
+	Community []deck.Card
+	Villains  []Villain
+	// Dead cards are dealt to nobody.
+	Dead []deck.Card
+}
+
+// CalculateVersus returns the odds of the hero and of every villain. Known
+// hands are dead cards for the board and for the other villains, and range
+// villains only hold hands that don't share cards with them. Every villain
+// hand and runout is played when there are few enough of them, otherwise
+// hands are sampled by weight.
+func (calc *OddsCalculator) CalculateVersus(request Request) (Odds, error) {
+
+	if len(request.Villains) < 1 || len(request.Villains) > 9 {
+		return Odds{Hero: handTypesMap()}, fmt.Errorf("between 1 and 9 villains supported")
+	}
+
+	if len(request.Hero) != 2 {
+		return Odds{Hero: handTypesMap()}, fmt.Errorf("please provide 2 hole cards for %s", request.Game)
+	}
+
+	known := [][]uint8{deck.Numbers(request.Hero)}
+	players := []PlayerOdds{{Hand: request.Hero}}
+	for i, villain := range request.Villains {
+		if len(villain.Hand) != 0 && len(villain.Hand) != 2 {
+			return Odds{Hero: handTypesMap()}, fmt.Errorf("please provide 2 hole cards for villain %d", i+1)
+		}
+		known = append(known, deck.Numbers(villain.Hand))
+
+		player := PlayerOdds{Hand: villain.Hand}
+		if len(villain.Hand) == 0 && villain.Range != nil {
+			player.Range = villain.Range.String()
+		}
+		players = append(players, player)
+	}
+
+	v, err := calc.newVersus(request.Game, request.Community, request.Dead, known...)
+
+	if err != nil {
+		return Odds{Hero: handTypesMap()}, err
+	}
+
+	v.addKnownSeat(known[0])
+	for i, villain := range request.Villains {
+		switch {
+		case len(villain.Hand) != 0:
+			v.addKnownSeat(known[i+1])
+		case villain.Range != nil:
+			if !v.addRangeSeat(*villain.Range) {
+				return Odds{Hero: handTypesMap()}, fmt.Errorf("villain %d has no hands left in their range", i+1)
+			}
+		default:
+			v.addRandomSeat()
+		}
+	}
+
+	if !v.hasDeal() {
+		return Odds{Hero: handTypesMap()}, fmt.Errorf("the villains' ranges always share cards")
+	}
+
+	results := v.run()
+
+	return results.odds(players), nil
+}
+
+// CalculateVersusRange returns the hero's odds against one villain holding a
+// hand from a weighted range.
+func (calc *OddsCalculator) CalculateVersusRange(game Game, heroCards []deck.Card, communityCards []deck.Card, villainRange ranges.Range) (Odds, error) {
+	return calc.CalculateVersus(Request{
+		Game:      game,
+		Hero:      heroCards,
+		Community: communityCards,
+		Villains:  []Villain{{Range: &villainRange}},
+	})
+}
+
+// CalculateRanges plays ranges against each other on a board, without a
+// known hand. It returns the odds of every range and of every combo of the
+// first range against the others. Combos only meet the combos of the other
+// ranges that don't share cards with them, so card removal is counted across
+// the whole ranges. Combos holding dead cards are left out.
+func (calc *OddsCalculator) CalculateRanges(game Game, communityCards []deck.Card, playerRanges []ranges.Range, deadCards ...deck.Card) (Odds, error) {
+
+	if len(playerRanges) < 2 || len(playerRanges) > 10 {
+		return Odds{Hero: handTypesMap()}, fmt.Errorf("between 2 and 10 ranges supported")
+	}
+
+	v, err := calc.newVersus(game, communityCards, deadCards)
+
+	if err != nil {
+		return Odds{Hero: handTypesMap()}, err
+	}
+
+	players := []PlayerOdds{}
+	for i, r := range playerRanges {
+		if !v.addRangeSeat(r) {
+			return Odds{Hero: handTypesMap()}, fmt.Errorf("range %d has no hands left", i+1)
+		}
+		players = append(players, PlayerOdds{Range: r.String()})
+	}
+
+	if !v.hasDeal() {
+		return Odds{Hero: handTypesMap()}, fmt.Errorf("the ranges always share cards")
+	}
+
+	results := v.run()
+
+	o := results.odds(players)
+	o.Combos = results.comboOdds(v.seats[0])
+
+	return o, nil
+}
+
+// newVersus prepares showdowns on a board, with the dead cards and the known
+// hands taken out of the cards left to deal.
+func (calc *OddsCalculator) newVersus(game Game, communityCards []deck.Card, deadCards []deck.Card, known ...[]uint8) (*versus, error) {
+
+	if game.holeCardsCount() != 2 || game.isStud() || game.isDraw() {
+		return nil, fmt.Errorf("villain hands and ranges are only supported for board games with two hole cards, such as hold'em")
+	}
+
+	for _, c := range append(append([]deck.Card{}, communityCards...), deadCards...) {
+		if !calc.deckFor(game).Contains(c) {
+			return nil, fmt.Errorf("%s is not in the %s deck", c, game)
+		}
+	}
+
+	community := deck.Numbers(communityCards)
+	dead := deck.Numbers(deadCards)
+
+	if len(community) != 0 && (len(community) < 3 || len(community) > 5) {
+		return nil, fmt.Errorf("please provide 0 or 3 or 4 or 5 community cards")
+	}
+
+	for _, hand := range known {
+		for _, c := range hand {
+			if !calc.deckFor(game).Contains(deck.Card(c)) {
+				return nil, fmt.Errorf("%s is not in the %s deck", deck.Card(c), game)
+			}
+		}
+	}
+
+	if duplicate, found := calc.hasDuplicates(append([][]uint8{community, dead}, known...)...); found {
+		return nil, fmt.Errorf("found more than one " + duplicate)
+	}
+
+	available, err := calc.available(game, append([][]uint8{community, dead}, known...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	v := &versus{
+		game:         game,
+		evaluator:    calc.evaluatorFor(game),
+		combinations: calc.combinations,
+		community:    community,
+		available:    available,
+		options:      calc.options,
+	}
+
+	return v, nil
+}
+
+// addKnownSeat seats a player whose hand is known.
+func (v *versus) addKnownSeat(hand []uint8) {
+	s := newSeat([]rangeCombo{{cards: [2]uint8{hand[0], hand[1]}, weight: 1}})
+	s.known = true
+	v.seats = append(v.seats, s)
+}
+
+// addRangeSeat seats a player holding the hands of a range that are still
+// available. It tells whether any are.
+func (v *versus) addRangeSeat(r ranges.Range) bool {
+
+	combos := []rangeCombo{}
+	for _, c := range r.Combos() {
+		if list.Includes(v.available, c[0].Number()) && list.Includes(v.available, c[1].Number()) {
+			combos = append(combos, rangeCombo{cards: [2]uint8{c[0].Number(), c[1].Number()}, weight: r.Weight(c)})
+		}
+	}
+
+	if len(combos) == 0 {
+		return false
+	}
+
+	v.seats = append(v.seats, newSeat(combos))
+	return true
+}
+
+// hasDeal tells whether the players can all hold hands that don't share
+// cards. The seats with the fewest combos are searched first, so that
+// conflicting ranges fail before the search goes through random hands.
+func (v *versus) hasDeal() bool {
+
+	order := make([]int, len(v.seats))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return len(v.seats[order[i]].combos) < len(v.seats[order[j]].combos)
+	})
+
+	return v.hasDealFrom(order, &deal{})
+}
+
+// hasDealFrom tells whether the seats in order can all hold hands that
+// don't share cards with each other or with the cards used.
+func (v *versus) hasDealFrom(order []int, d *deal) bool {
+
+	if len(order) == 0 {
+		return true
+	}
+
+	for _, c := range v.seats[order[0]].combos {
+		if d.used[c.cards[0]] || d.used[c.cards[1]] {
+			continue
+		}
+		d.used[c.cards[0]], d.used[c.cards[1]] = true, true
+		found := v.hasDealFrom(order[1:], d)
+		d.used[c.cards[0]], d.used[c.cards[1]] = false, false
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addRandomSeat seats a villain holding any two available cards.
+func (v *versus) addRandomSeat() {
+
+	combos := []rangeCombo{}
+	for i, a := range v.available {
+		for _, b := range v.available[i+1:] {
+			combos = append(combos, rangeCombo{cards: [2]uint8{a, b}, weight: 1})
+		}
+	}
+
+	v.seats = append(v.seats, newSeat(combos))
+}
+
+// exactShowDowns estimates how many showdowns enumerating every hand and
+// runout takes, ignoring conflicts between players.
+func (v *versus) exactShowDowns() float64 {
+
+	showDowns := 1.0
+	remaining := len(v.available)
+	for _, s := range v.seats {
+		showDowns *= float64(len(s.combos))
+		if !s.known {
+			remaining -= 2
+		}
+	}
+
+	for i := 0; i < remainingCommunityCardsCount(v.community); i++ {
+		showDowns *= float64(remaining-i) / float64(i+1)
+	}
+
+	return showDowns
+}
+
+func (v *versus) run() versusResults {
+
+	budget := v.options.newBudget()
+	showDownsDesired := int(v.options.showDownsDesired(versusShowDownsDesired))
+	exactShowDowns := v.exactShowDowns()
+	exact := exactShowDowns <= v.options.showDownsDesired(exactVersusLimit)
+	spread := v.spreadSeat()
+
+	total := v.newResults()
+	total.enumerated = exact
+	total.spread = spread
+	total.unitCount = math.Inf(1)
+
+	if exact {
+		order := make([]int, len(v.seats[spread].combos))
+		for i := range order {
+			order[i] = i
+		}
+		if v.options.TimeBudget > 0 {
+			// The budget can run out before the last combo, the combos go in
+			// a random order for the ones played to be a fair sample. The
+			// order is always the same to keep exact results the same.
+			order = rand.New(rand.NewSource(1)).Perm(len(order))
+		}
+
+		// Workers always get the same combos to enumerate and are added in
+		// order, so exact results come out the same to the last bit.
+		for _, r := range parallel(func(w int, workerCount int) versusResults {
+			return v.enumerate(spread, order, w, workerCount, budget)
+		}) {
+			total.add(r)
+		}
+
+		total.unitCount = float64(len(order))
+		total.exact = total.spreadCombos == len(order)
+		planned := total.counts.Total
+		if !total.exact {
+			planned = int(exactShowDowns)
+		}
+		total.run = budget.run(1, planned, !total.exact, total.precision(0), total.exact)
+
+		return total
+	}
+
+	rounds := 0
+	for _, roundSize := range budget.rounds(showDownsDesired) {
+
+		seed := time.Now().UnixNano()
+		for _, r := range parallel(func(w int, workerCount int) versusResults {
+			showDowns := roundSize / workerCount
+			if w < roundSize%workerCount {
+				showDowns++
+			}
+			return v.sample(showDowns, seed+int64(w), budget)
+		}) {
+			total.add(r)
+		}
+
+		rounds++
+		if budget.expired() || budget.precise(total.precision(0), false) {
+			break
+		}
+	}
+
+	total.run = budget.run(rounds, showDownsDesired, total.counts.Total < showDownsDesired, total.precision(0), false)
+
+	return total
+}
+
+// parallel runs work on every CPU and returns the results of each worker in
+// order.
+func parallel(work func(worker int, workerCount int) versusResults) []versusResults {
+
+	workerCount := runtime.NumCPU()
+	results := make([]versusResults, workerCount)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workerCount; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			results[w] = work(w, workerCount)
+		}(w)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (v *versus) newResults() versusResults {
+	return newVersusResults(len(v.seats), len(v.seats[0].combos))
+}
+
+// spreadSeat returns the seat whose combos are shared out between the
+// workers enumerating: the first unless it holds a single hand, then the
+// seat with the most combos. A cut short enumeration then always plays the
+// first seat's combos whole.
+func (v *versus) spreadSeat() int {
+	spread := 0
+	if len(v.seats[0].combos) > 1 {
+		return spread
+	}
+	for i, s := range v.seats {
+		if len(s.combos) > len(v.seats[spread].combos) {
+			spread = i
+		}
+	}
+	return spread
+}
+
+// deal holds the cards of one showdown being put together.
+type deal struct {
+	used  [53]bool
+	holes [][2]uint8
+	// picks holds the index of the combo each player holds.
+	picks  []int
+	board  []uint8
+	rest   []uint8
+	hand   []uint8
+	values []uint32
+}
+
+func (v *versus) newDeal() *deal {
+	d := &deal{
+		holes:  make([][2]uint8, len(v.seats)),
+		picks:  make([]int, len(v.seats)),
+		board:  make([]uint8, 5),
+		rest:   make([]uint8, len(v.available)),
+		hand:   make([]uint8, 7),
+		values: make([]uint32, len(v.seats)),
+	}
+	copy(d.board, v.community)
+	return d
+}
+
+// remaining gathers the available cards no player holds.
+func (d *deal) remaining(available []uint8) []uint8 {
+	n := 0
+	for _, c := range available {
+		if !d.used[c] {
+			d.rest[n] = c
+			n++
+		}
+	}
+	return d.rest[:n]
+}
+
+// sample plays showdowns with hands drawn by weight. Hands are drawn for every
+// player independently and drawn again when they share a card, which keeps
+// the odds of each combination of hands right.
+func (v *versus) sample(showDowns int, seed int64, budget budget) versusResults {
+
+	rGen := rand.New(rand.NewSource(seed))
+	d := v.newDeal()
+	missing := remainingCommunityCardsCount(v.community)
+	r := v.newResults()
+	before := make([]playerResults, len(v.seats))
+
+	for s := 0; s < showDowns; {
+
+		if s > 0 && s%budgetCheckInterval == 0 && budget.expired() {
+			break
+		}
+
+		conflict := false
+		for i := range v.seats {
+			d.picks[i] = v.seats[i].pick(rGen)
+			c := v.seats[i].combos[d.picks[i]]
+			if d.used[c.cards[0]] || d.used[c.cards[1]] {
+				conflict = true
+			}
+			d.used[c.cards[0]], d.used[c.cards[1]] = true, true
+			d.holes[i] = c.cards
+			if conflict {
+				break
+			}
+		}
+
+		if !conflict {
+			rest := d.remaining(v.available)
+			for i := 0; i < missing; i++ {
+				j := i + rGen.Intn(len(rest)-i)
+				rest[i], rest[j] = rest[j], rest[i]
+			}
+			copy(d.board[len(v.community):], rest[:missing])
+			weightBefore := r.snapshot(before)
+			v.settle(d, 1, &r)
+			r.addUnit(before, weightBefore)
+			s++
+		}
+
+		d.used = [53]bool{}
+	}
+
+	return r
+}
+
+// enumerate plays every showdown with the spread seat holding one of the
+// combos of the worker, every workerCount-th of order starting from worker.
+func (v *versus) enumerate(spread int, order []int, worker int, workerCount int, budget budget) versusResults {
+
+	d := v.newDeal()
+	r := v.newResults()
+	before := make([]playerResults, len(v.seats))
+
+	for o := worker; o < len(order); o += workerCount {
+		i := order[o]
+		c := v.seats[spread].combos[i]
+		d.used[c.cards[0]], d.used[c.cards[1]] = true, true
+		d.holes[spread] = c.cards
+		d.picks[spread] = i
+		weightBefore := r.snapshot(before)
+		v.enumerateSeat(d, 0, spread, c.weight, &r)
+		r.addUnit(before, weightBefore)
+		d.used[c.cards[0]], d.used[c.cards[1]] = false, false
+		r.spreadCombos++
+		if budget.expired() {
+			break
+		}
+	}
+
+	return r
+}
+
+func (v *versus) enumerateSeat(d *deal, seatIndex int, spread int, weight float64, r *versusResults) {
+
+	if seatIndex == len(v.seats) {
+		v.enumerateRunouts(d, weight, r)
+		return
+	}
+
+	if seatIndex == spread {
+		v.enumerateSeat(d, seatIndex+1, spread, weight, r)
+		return
+	}
+
+	for i, c := range v.seats[seatIndex].combos {
+		if d.used[c.cards[0]] || d.used[c.cards[1]] {
+			continue
+		}
+		d.used[c.cards[0]], d.used[c.cards[1]] = true, true
+		d.holes[seatIndex] = c.cards
+		d.picks[seatIndex] = i
+		v.enumerateSeat(d, seatIndex+1, spread, weight*c.weight, r)
+		d.used[c.cards[0]], d.used[c.cards[1]] = false, false
+	}
+}
+
+func (v *versus) enumerateRunouts(d *deal, weight float64, r *versusResults) {
+
+	missing := remainingCommunityCardsCount(v.community)
+	if missing == 0 {
+		v.settle(d, weight, r)
+		return
+	}
+
+	rest := list.Clone(d.remaining(v.available))
+	runouts, err := v.combinations.Get(uint8(len(rest)), uint8(missing))
+
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for _, runout := range runouts {
+		list.CopyValuesAtIndexes(d.board[len(v.community):], rest, runout)
+		v.settle(d, weight, r)
+	}
+}
+
+// settle plays one showdown between the players' hands, the first seat's
+// counted as the hero's.
+func (v *versus) settle(d *deal, weight float64, r *versusResults) {
+
+	copy(d.hand, d.board)
+
+	var best uint32
+	var heroHandTypeIndex uint32
+	for i := range d.holes {
+		copy(d.hand[5:], d.holes[i][:])
+		value, handTypeIndex := v.evaluator.Eval(d.hand...)
+		if i == 0 {
+			if handTypeIndex == handevaluator.InvalidHandIndex {
+				panic("invalid hand for hero")
+			}
+			heroHandTypeIndex = handTypeIndex
+		}
+		d.values[i] = value
+		if value > best {
+			best = value
+		}
+	}
+	heroValue := d.values[0]
+
+	winners := 0
+	for _, value := range d.values {
+		if value == best {
+			winners++
+		}
+	}
+
+	for i, value := range d.values {
+		r.players[i].settle(value, best, winners, weight)
+	}
+
+	r.combos[d.picks[0]].settle(heroValue, best, winners, weight)
+	r.comboWeights[d.picks[0]] += weight
+
+	r.counts.Total++
+	r.weight += weight
+	r.hero[heroHandTypeIndex]++
+
+	switch {
+	case heroValue < best:
+		r.counts.Lose++
+	case winners == 1:
+		r.counts.Win++
+	default:
+		r.counts.Tie++
+		r.tieVillainCounts[winners-1]++
+	}
+}
@@ -0,0 +1,159 @@
+package ranges
+
+import (
+	"holdem/deck"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// String writes the range in the shortest usual notation: complete hands
+// such as AKs are grouped into runs like "TT+", "AQs+" or "A5s-A2s" and the
+// remaining combos are listed one by one. Combos played less than every time
+// are written by weight, most played first, with the weight after each part
+// as in "AKo:0.5".
+func (r Range) String() string {
+
+	byWeight := map[float64]Range{}
+	weights := []float64{}
+
+	for c, weight := range r.combos {
+		if _, ok := byWeight[weight]; !ok {
+			byWeight[weight] = New()
+			weights = append(weights, weight)
+		}
+		byWeight[weight].combos[c] = 1
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(weights)))
+
+	parts := []string{}
+	for _, weight := range weights {
+		for _, part := range byWeight[weight].notation() {
+			if weight != 1 {
+				part += ":" + strconv.FormatFloat(weight, 'f', -1, 64)
+			}
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// notation writes the parts of a range, ignoring weights.
+func (r Range) notation() []string {
+
+	parts := []string{}
+	covered := map[Combo]struct{}{}
+
+	complete := func(h handClass) bool {
+		for _, c := range h.combos() {
+			if !r.Contains(c) {
+				return false
+			}
+		}
+		return true
+	}
+
+	cover := func(h handClass) {
+		for _, c := range h.combos() {
+			covered[c] = struct{}{}
+		}
+	}
+
+	// Pairs, from aces down.
+	var pairRanks []deck.Rank
+	for rank := deck.Ace; ; rank-- {
+		h := handClass{high: rank, low: rank, suited: true, offsuit: true}
+		if complete(h) {
+			cover(h)
+			pairRanks = append(pairRanks, rank)
+		}
+		if rank == deck.Two {
+			break
+		}
+	}
+	parts = append(parts, formatRuns(pairRanks, deck.Ace, func(rank deck.Rank) string {
+		return rankName(rank) + rankName(rank)
+	})...)
+
+	// Other hands by their high card, both suited and offsuit first.
+	for high := deck.Ace; high > deck.Two; high-- {
+		for _, kind := range []struct {
+			suffix          string
+			suited, offsuit bool
+		}{{"", true, true}, {"s", true, false}, {"o", false, true}} {
+
+			var kickers []deck.Rank
+			for low := high - 1; ; low-- {
+				h := handClass{high: high, low: low, suited: kind.suited, offsuit: kind.offsuit}
+				if !isCovered(h, covered) && complete(h) {
+					cover(h)
+					kickers = append(kickers, low)
+				}
+				if low == deck.Two {
+					break
+				}
+			}
+
+			parts = append(parts, formatRuns(kickers, high-1, func(low deck.Rank) string {
+				return rankName(high) + rankName(low) + kind.suffix
+			})...)
+		}
+	}
+
+	for _, c := range r.Combos() {
+		if _, ok := covered[c]; !ok {
+			parts = append(parts, c.String())
+		}
+	}
+
+	return parts
+}
+
+// rankName writes ranks upper case, as range notation does.
+func rankName(r deck.Rank) string {
+	return strings.ToUpper(r.String())
+}
+
+func cardName(c deck.Card) string {
+	return rankName(c.Rank()) + c.Suit().String()
+}
+
+// isCovered tells whether a class has already been written, so that a suited
+// run doesn't repeat hands written without a suffix.
+func isCovered(h handClass, covered map[Combo]struct{}) bool {
+	for _, c := range h.combos() {
+		if _, ok := covered[c]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// formatRuns writes descending ranks as runs of consecutive ranks. A run
+// starting at top is written with a +.
+func formatRuns(ranks []deck.Rank, top deck.Rank, name func(deck.Rank) string) []string {
+
+	parts := []string{}
+
+	for start := 0; start < len(ranks); {
+		end := start
+		for end+1 < len(ranks) && ranks[end+1] == ranks[end]-1 {
+			end++
+		}
+
+		switch {
+		case ranks[start] == top && end > start:
+			parts = append(parts, name(ranks[end])+"+")
+		case end > start:
+			parts = append(parts, name(ranks[start])+"-"+name(ranks[end]))
+		default:
+			parts = append(parts, name(ranks[start]))
+		}
+
+		start = end + 1
+	}
+
+	return parts
+}
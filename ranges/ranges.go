@@ -0,0 +1,289 @@
+package ranges
+
+import (
+	"fmt"
+	"holdem/deck"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Combo is a two card hand, the higher card first.
+type Combo [2]deck.Card
+
+func NewCombo(a deck.Card, b deck.Card) Combo {
+	if a < b {
+		a, b = b, a
+	}
+	return Combo{a, b}
+}
+
+func (c Combo) String() string {
+	return cardName(c[0]) + cardName(c[1])
+}
+
+// Overlaps tells whether the combo holds any of the cards.
+func (c Combo) Overlaps(cards ...deck.Card) bool {
+	for _, card := range cards {
+		if c[0] == card || c[1] == card {
+			return true
+		}
+	}
+	return false
+}
+
+// Range is a set of two card hands, each played with a weight between 0 and
+// 1: how often the hand is in the range when holding it, as in mixed
+// strategies.
+type Range struct {
+	combos map[Combo]float64
+}
+
+// New returns a range holding every combo at full weight.
+func New(combos ...Combo) Range {
+	r := Range{combos: map[Combo]float64{}}
+	for _, c := range combos {
+		r.combos[c] = 1
+	}
+	return r
+}
+
+func (r Range) Len() int {
+	return len(r.combos)
+}
+
+func (r Range) Contains(c Combo) bool {
+	_, ok := r.combos[c]
+	return ok
+}
+
+// Weight returns how often the combo is in the range, 0 when it never is.
+func (r Range) Weight(c Combo) float64 {
+	return r.combos[c]
+}
+
+// Set puts a combo in the range with a weight, or takes it out with 0.
+func (r Range) Set(c Combo, weight float64) {
+	if weight <= 0 {
+		delete(r.combos, c)
+		return
+	}
+	r.combos[c] = weight
+}
+
+// Combos returns the combos of the range, best first.
+func (r Range) Combos() []Combo {
+
+	combos := make([]Combo, 0, len(r.combos))
+	for c := range r.combos {
+		combos = append(combos, c)
+	}
+
+	sort.Slice(combos, func(i, j int) bool {
+		if combos[i][0] != combos[j][0] {
+			return combos[i][0] > combos[j][0]
+		}
+		return combos[i][1] > combos[j][1]
+	})
+
+	return combos
+}
+
+// Without returns the range without the combos holding any of the dead
+// cards, such as the hero's hand or the board.
+func (r Range) Without(dead ...deck.Card) Range {
+
+	remaining := New()
+	for c, weight := range r.combos {
+		if !c.Overlaps(dead...) {
+			remaining.combos[c] = weight
+		}
+	}
+	return remaining
+}
+
+// Parse reads a range in the usual notation, such as
+// "TT+, AQs+, KQo, A5s-A2s, 76s, AhKh": pairs, suited (s) and offsuit (o)
+// hands or both when neither is given, a + for every better kicker or pair,
+// a dash between two hands with the same high card, and single combos.
+// A weight can follow any part, as in "AKo:0.5" or "QQ:75%"; parts without
+// one are played every time, and later parts override earlier ones.
+func Parse(s string) (Range, error) {
+
+	r := New()
+
+	tokens := strings.FieldsFunc(s, func(c rune) bool {
+		return c == ',' || c == ' ' || c == '\t' || c == '\n'
+	})
+
+	for _, token := range tokens {
+		weight := 1.0
+		if colon := strings.Index(token, ":"); colon >= 0 {
+			w, err := parseWeight(token[colon+1:])
+			if err != nil {
+				return Range{}, fmt.Errorf("%q has an invalid weight: %v", token, err)
+			}
+			token, weight = token[:colon], w
+		}
+
+		combos, err := parseToken(token)
+		if err != nil {
+			return Range{}, err
+		}
+		for _, c := range combos {
+			r.Set(c, weight)
+		}
+	}
+
+	return r, nil
+}
+
+// parseWeight reads a weight between 0 and 1, or a percentage.
+func parseWeight(s string) (float64, error) {
+
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s, scale = strings.TrimSuffix(s, "%"), 100
+	}
+
+	w, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	w /= scale
+	if math.IsNaN(w) || w < 0 || w > 1 {
+		return 0, fmt.Errorf("%v is not between 0 and 1", w)
+	}
+	return w, nil
+}
+
+// handClass is one of the 169 kinds of starting hands, such as AKs.
+type handClass struct {
+	high   deck.Rank
+	low    deck.Rank
+	suited bool
+	// offsuit and suited are both set when the class doesn't say, as in AK.
+	offsuit bool
+}
+
+func (h handClass) combos() []Combo {
+
+	combos := []Combo{}
+
+	for s1 := deck.Clubs; s1 <= deck.Spades; s1++ {
+		for s2 := deck.Clubs; s2 <= deck.Spades; s2++ {
+			if h.high == h.low && s2 <= s1 {
+				continue
+			}
+			if h.high != h.low && (s1 == s2 && !h.suited || s1 != s2 && !h.offsuit) {
+				continue
+			}
+			combos = append(combos, NewCombo(deck.NewCard(h.high, s1), deck.NewCard(h.low, s2)))
+		}
+	}
+
+	return combos
+}
+
+func parseRank(c byte) (deck.Rank, bool) {
+	index := strings.IndexByte("23456789tjqka", c|0x20)
+	return deck.Rank(index), index >= 0
+}
+
+// parseClass reads hands such as "TT", "AKs", "AKo" or "AK".
+func parseClass(s string) (handClass, bool) {
+
+	if len(s) < 2 || len(s) > 3 {
+		return handClass{}, false
+	}
+
+	high, okHigh := parseRank(s[0])
+	low, okLow := parseRank(s[1])
+	if !okHigh || !okLow {
+		return handClass{}, false
+	}
+	if high < low {
+		high, low = low, high
+	}
+
+	h := handClass{high: high, low: low, suited: true, offsuit: true}
+
+	if len(s) == 3 {
+		switch s[2] | 0x20 {
+		case 's':
+			h.offsuit = false
+		case 'o':
+			h.suited = false
+		default:
+			return handClass{}, false
+		}
+		if high == low {
+			return handClass{}, false
+		}
+	}
+
+	return h, true
+}
+
+func parseToken(token string) ([]Combo, error) {
+
+	invalid := fmt.Errorf("%q is not a hand range", token)
+
+	if dash := strings.Index(token, "-"); dash >= 0 {
+		from, okFrom := parseClass(token[:dash])
+		to, okTo := parseClass(token[dash+1:])
+		if !okFrom || !okTo || from.suited != to.suited || from.offsuit != to.offsuit {
+			return nil, invalid
+		}
+		if from.high == from.low && to.high == to.low {
+			return classRun(from, to.low, from.low, true), nil
+		}
+		if from.high != to.high || from.high == from.low || to.high == to.low {
+			return nil, invalid
+		}
+		return classRun(from, to.low, from.low, false), nil
+	}
+
+	if strings.HasSuffix(token, "+") {
+		h, ok := parseClass(strings.TrimSuffix(token, "+"))
+		if !ok {
+			return nil, invalid
+		}
+		if h.high == h.low {
+			return classRun(h, h.low, deck.Ace, true), nil
+		}
+		return classRun(h, h.low, h.high-1, false), nil
+	}
+
+	if h, ok := parseClass(token); ok {
+		return h.combos(), nil
+	}
+
+	cards, err := deck.ParseHand(token)
+	if err != nil || len(cards) != 2 || cards[0] == cards[1] {
+		return nil, invalid
+	}
+	return []Combo{NewCombo(cards[0], cards[1])}, nil
+}
+
+// classRun returns the combos of every hand like h with its low card, or
+// both cards for pairs, from lowest to highest.
+func classRun(h handClass, lowest deck.Rank, highest deck.Rank, pairs bool) []Combo {
+
+	if lowest > highest {
+		lowest, highest = highest, lowest
+	}
+
+	combos := []Combo{}
+	for r := lowest; r <= highest; r++ {
+		class := h
+		class.low = r
+		if pairs {
+			class.high = r
+		}
+		combos = append(combos, class.combos()...)
+	}
+	return combos
+}
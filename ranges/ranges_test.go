@@ -0,0 +1,124 @@
+package ranges
+
+import (
+	"holdem/deck"
+	"testing"
+)
+
+func TestParseRejectsInvalidWeights(t *testing.T) {
+	for _, s := range []string{"AK:NaN, QQ", "AK:nan%", "AK:-0.5", "AK:1.5", "AK:150%", "AK:Inf", "AK:", "AK:x"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
+
+func combo(t *testing.T, s string) Combo {
+	t.Helper()
+	cards, err := deck.ParseHand(s)
+	if err != nil || len(cards) != 2 {
+		t.Fatalf("%q is not a combo", s)
+	}
+	return NewCombo(cards[0], cards[1])
+}
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		in     string
+		combos int
+		out    string
+	}{
+		{"AA", 6, "AA"},
+		{"AKs", 4, "AKs"},
+		{"AKo", 12, "AKo"},
+		{"AK", 16, "AK"},
+		{"AKs, AKo", 16, "AK"},
+		{"22+", 78, "22+"},
+		{"TT-77", 24, "TT-77"},
+		{"KTo+", 36, "KTo+"},
+		{"A5s-A2s", 16, "A5s-A2s"},
+		{"AhKh", 1, "AhKh"},
+		{"TT+, AQs+, KQo, A5s-A2s, 76s, AhKh", 70, "TT+, AQs+, A5s-A2s, KQo, 76s"},
+		{"QQ, AKs, AhKd", 11, "QQ, AKs, AhKd"},
+		{"tt+ aqs+", 38, "TT+, AQs+"},
+	}
+
+	for _, test := range tests {
+		r, err := Parse(test.in)
+		if err != nil {
+			t.Errorf("%q: %v", test.in, err)
+			continue
+		}
+		if r.Len() != test.combos {
+			t.Errorf("%q has %d combos, expected %d", test.in, r.Len(), test.combos)
+		}
+		if out := r.String(); out != test.out {
+			t.Errorf("%q is written %q, expected %q", test.in, out, test.out)
+		}
+
+		again, err := Parse(r.String())
+		if err != nil {
+			t.Errorf("%q doesn't parse back: %v", r.String(), err)
+			continue
+		}
+		if again.Len() != r.Len() {
+			t.Errorf("%q parses back to %d combos, expected %d", r.String(), again.Len(), r.Len())
+		}
+		for _, c := range r.Combos() {
+			if again.Weight(c) != r.Weight(c) {
+				t.Errorf("%q parses back with %s at %v, expected %v", r.String(), c, again.Weight(c), r.Weight(c))
+			}
+		}
+	}
+}
+
+func TestParseRejectsInvalidRanges(t *testing.T) {
+	for _, s := range []string{"AKx", "A", "ZZ", "AAs", "AK-QJ", "AKs-A2o", "AhAh", "AhKhQh"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
+
+func TestWeights(t *testing.T) {
+
+	r, err := Parse("JJ+, QQ:75%, AKo:0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	weights := map[string]float64{"AsAh": 1, "QsQh": 0.75, "JsJh": 1, "AsKh": 0.5, "AsKs": 0}
+	for hand, weight := range weights {
+		if w := r.Weight(combo(t, hand)); w != weight {
+			t.Errorf("%s has weight %v, expected %v", hand, w, weight)
+		}
+	}
+
+	if s := r.String(); s != "KK+, JJ, QQ:0.75, AKo:0.5" {
+		t.Errorf("written %q", s)
+	}
+}
+
+func TestWithout(t *testing.T) {
+
+	r, err := Parse("AA, AKs:0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dead := combo(t, "AhKd")
+	without := r.Without(dead[:]...)
+	if without.Len() != 5 {
+		t.Errorf("%d combos left, expected 5", without.Len())
+	}
+	if without.Contains(combo(t, "AhAs")) || without.Contains(combo(t, "AdKd")) {
+		t.Errorf("%s holds dead cards", without)
+	}
+	if w := without.Weight(combo(t, "AsKs")); w != 0.5 {
+		t.Errorf("AsKs has weight %v, expected 0.5", w)
+	}
+	if r.Len() != 10 {
+		t.Errorf("Without changed the range")
+	}
+}